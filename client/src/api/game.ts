@@ -7,11 +7,33 @@ export enum JoinType {
   LINK = 'link'
 }
 
+export type Seating = 'random' | 'host'
+
+export type OfAKind = 'double' | 'add'
+
+export interface Rules {
+  opening_score: number
+  farkle_penalty: number
+  of_a_kind: OfAKind
+  partial_straights: boolean
+  catch_up: boolean
+}
+
 export interface CreateGameInput {
   currency_id: number
   bet: number
   winning_points: number
   join_type: JoinType
+  min_players?: number
+  max_players?: number
+  seating?: Seating
+  // seconds; 0 or left out turns a limit off
+  turn_seconds?: number
+  game_seconds?: number
+  // house rules; left out the standard rules apply
+  rules?: Partial<Rules>
+  // left out the game is open to spectators
+  allow_spectators?: boolean
 }
 
 export interface Game {
@@ -20,7 +42,15 @@ export interface Game {
   bet: number
   winning_points: number
   link: string
+  min_players: number
+  max_players: number
+  seating: Seating
+  turn_seconds: number
+  game_seconds: number
+  rules: Rules
   currency: Currency
+  allow_spectators: boolean
+  spectators: number
 }
 
 export const createGame = async (input: CreateGameInput): Promise<Game> => {
@@ -30,3 +60,126 @@ export const createGame = async (input: CreateGameInput): Promise<Game> => {
 
   return data.data;
 }
+
+export interface Player {
+  id: number
+  username: string
+  score: number
+  is_winner: boolean
+  seat: number
+  ready: boolean
+  farkles: number
+  forfeited?: boolean
+  time_left?: number
+}
+
+export interface GameState {
+  code: string
+  creator_id: number
+  min_players: number
+  max_players: number
+  seating: Seating
+  started: boolean
+  finished: boolean
+  winning_points: number
+  turn: number
+  phase: string
+  current_user_id: number
+  dice: number[]
+  dice_types: string[]
+  dice_left: number
+  turn_score: number
+  players: Player[]
+  server_seed_hash: string
+  turn_seconds: number
+  game_seconds: number
+  turn_deadline: string | null
+  turn_time_left: number | null
+  rules: Rules
+  last_round: boolean
+  last_turns: number[]
+  allow_spectators: boolean
+  spectators: number
+}
+
+export const joinGame = async (code: string): Promise<GameState> => {
+  const {data} = await fetchApi.post(`/games/${code}/join`)
+
+  return data;
+}
+
+export const cancelGame = async (code: string) => {
+  await fetchApi.delete(`/games/${code}`)
+}
+
+export const leaveGame = async (code: string) => {
+  await fetchApi.post(`/games/${code}/leave`)
+}
+
+export const forfeitGame = async (code: string) => {
+  await fetchApi.post(`/games/${code}/forfeit`)
+}
+
+export const setReady = async (code: string, ready: boolean): Promise<GameState> => {
+  const {data} = ready
+    ? await fetchApi.post(`/games/${code}/ready`)
+    : await fetchApi.delete(`/games/${code}/ready`)
+
+  return data;
+}
+
+export const kickPlayer = async (code: string, userId: number): Promise<GameState> => {
+  const {data} = await fetchApi.delete(`/games/${code}/players/${userId}`)
+
+  return data;
+}
+
+export const setSeats = async (code: string, order: number[]): Promise<GameState> => {
+  const {data} = await fetchApi.put(`/games/${code}/seats`, {order})
+
+  return data;
+}
+
+export interface LobbyGame {
+  code: string
+  bet: number
+  winning_points: number
+  players: number
+  min_players: number
+  max_players: number
+  currency: Currency
+  creator: { id: number; username: string }
+  created_at: string
+}
+
+export interface ListGamesParams {
+  currency_id?: number
+  min_bet?: number
+  max_bet?: number
+  sort?: 'newest' | 'oldest' | 'bet' | 'winning_points'
+  order?: 'asc' | 'desc'
+  cursor?: string
+  limit?: number
+}
+
+export const listGames = async (params: ListGamesParams = {}): Promise<{ data: LobbyGame[]; next_cursor: string }> => {
+  const {data} = await fetchApi.get('/games', {params})
+
+  return {data: data.data, next_cursor: data.meta.next_cursor};
+}
+
+export type BotStrategy = 'cautious' | 'greedy' | 'optimal'
+
+export const addBot = async (code: string, strategy: BotStrategy): Promise<GameState> => {
+  const {data} = await fetchApi.post(`/games/${code}/bots`, {strategy})
+
+  return data;
+}
+
+export const setSpectating = async (code: string, allow: boolean): Promise<GameState> => {
+  const {data} = allow
+    ? await fetchApi.post(`/games/${code}/spectators`)
+    : await fetchApi.delete(`/games/${code}/spectators`)
+
+  return data;
+}
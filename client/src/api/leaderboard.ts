@@ -0,0 +1,35 @@
+import fetchApi from "@/packages/fetchApi.ts";
+
+export type LeaderboardMetric = 'winnings' | 'wins' | 'win_rate' | 'best_turn'
+export type LeaderboardWindow = 'all' | 'month' | 'week'
+
+export interface LeaderboardEntry {
+  rank: number
+  user_id: number
+  username: string
+  games: number
+  wins: number
+  value: number
+}
+
+export interface Leaderboard {
+  metric: LeaderboardMetric
+  window: LeaderboardWindow
+  since: string | null
+  data: LeaderboardEntry[]
+  me: LeaderboardEntry | null
+}
+
+export interface LeaderboardParams {
+  metric?: LeaderboardMetric
+  window?: LeaderboardWindow
+  currency_id?: number
+  min_games?: number
+  limit?: number
+}
+
+export const getLeaderboard = async (params: LeaderboardParams = {}): Promise<Leaderboard> => {
+  const {data} = await fetchApi.get('/leaderboard', {params})
+
+  return data;
+}
@@ -0,0 +1,67 @@
+import fetchApi from "@/packages/fetchApi.ts";
+import type {GameState} from "@/api/game.ts";
+
+// Wildcard is the devil's head face; it counts as any face.
+export const WILDCARD = 0
+
+export type ItemKind = 'die' | 'badge'
+
+export interface Die {
+  slug: string
+  name: string
+  description: string
+  faces: number[]
+  chances: number[]
+  price: number
+  currency?: string
+}
+
+export interface Badge {
+  slug: string
+  name: string
+  description: string
+  price: number
+  currency: string
+}
+
+export interface InventoryItem {
+  kind: ItemKind
+  slug: string
+  quantity: number
+}
+
+export const getDice = async (): Promise<Die[]> => {
+  const {data} = await fetchApi.get('/dice')
+
+  return data.data;
+}
+
+export const getBadges = async (): Promise<Badge[]> => {
+  const {data} = await fetchApi.get('/badges')
+
+  return data.data;
+}
+
+export const getInventory = async (): Promise<InventoryItem[]> => {
+  const {data} = await fetchApi.get('/profile/inventory')
+
+  return data.data;
+}
+
+export const purchase = async (kind: ItemKind, slug: string, quantity = 1): Promise<InventoryItem[]> => {
+  const {data} = await fetchApi.post('/profile/inventory', {kind, slug, quantity})
+
+  return data.data;
+}
+
+export const setLoadout = async (code: string, dice: string[], badge = ''): Promise<GameState> => {
+  const {data} = await fetchApi.put(`/games/${code}/loadout`, {dice, badge})
+
+  return data;
+}
+
+export const reroll = async (code: string, die: number) => {
+  const {data} = await fetchApi.post(`/games/${code}/reroll`, {die})
+
+  return data;
+}
@@ -17,3 +17,66 @@ export const getProfile = async () => {
 
   return data.data as User;
 }
+
+export const changePassword = async (currentPassword: string, newPassword: string) => {
+  await fetchApi.put('/profile/password', {current_password: currentPassword, new_password: newPassword});
+}
+
+export const changeUsername = async (username: string) => {
+  const { data } = await fetchApi.put('/profile/username', {username});
+
+  return data.data as User;
+}
+
+export const deleteAccount = async (password: string) => {
+  await fetchApi.delete('/profile', {data: {password}});
+}
+
+export interface Opponent {
+  id: number
+  username: string
+  score: number
+  is_winner: boolean
+}
+
+export interface Match {
+  code: string
+  result: 'win' | 'loss'
+  score: number
+  opponents: Opponent[]
+  bet: number
+  payout: number
+  currency: Currency
+  winning_points: number
+  duration_seconds: number
+  started_at: string
+  finished_at: string
+}
+
+export interface MatchesParams {
+  page?: number
+  per_page?: number
+  result?: 'win' | 'loss'
+}
+
+export const getMatches = async (params: MatchesParams = {}): Promise<Match[]> => {
+  const { data } = await fetchApi.get('/profile/matches', { params });
+
+  return data.data;
+}
+
+export interface Achievement {
+  slug: string
+  title: string
+  description: string
+  goal: number
+  progress: number
+  unlocked: boolean
+  unlocked_at: string | null
+}
+
+export const getAchievements = async (): Promise<Achievement[]> => {
+  const { data } = await fetchApi.get('/profile/achievements');
+
+  return data.data;
+}
@@ -1,4 +1,11 @@
 import axios from 'axios';
+
+declare module 'axios' {
+  interface AxiosRequestConfig {
+    skipRefresh?: boolean
+    retried?: boolean
+  }
+}
 import {useAuthStore} from "../stores/auth.ts";
 import {useToast} from "../composables/useToast.ts";
 
@@ -36,6 +43,15 @@ fetchApi.interceptors.response.use(
   },
   async (error) => {
     const errorResponse = error?.response;
+    const config = error?.config;
+
+    if (errorResponse?.status === 401 && config && !config.skipRefresh && !config.retried) {
+      config.retried = true;
+
+      if (await useAuthStore().refresh()) {
+        return fetchApi(config);
+      }
+    }
 
     if (errorResponse?.data?.errors) {
       const errors = errorResponse?.data?.errors
@@ -55,7 +71,7 @@ fetchApi.interceptors.response.use(
       useToast().push({title: 'Error', message: error?.message, kind: 'error'})
     }
 
-    if (errorResponse?.status === 401) {
+    if (errorResponse?.status === 401 && !config?.skipRefresh) {
       window.location.href = '/login';
     }
 
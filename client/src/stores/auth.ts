@@ -2,19 +2,22 @@ import { defineStore } from "pinia";
 import fetchApi from "@/packages/fetchApi.ts";
 import {getProfile, type User} from "@/api/user.ts";
 import router from "@/routes";
-import {useToast} from "@/composables/useToast.ts";
 
 const ACCESS_TOKEN = "access_token";
+const REFRESH_TOKEN = "refresh_token";
 
 interface LoginResponse {
   message: string
   token: string
+  refresh_token: string
+  expires_in: number
 }
 
 export const useAuthStore = defineStore("auth", {
   state: () => ({
     user: (null as User | null),
     token: localStorage.getItem(ACCESS_TOKEN) || "",
+    refreshToken: localStorage.getItem(REFRESH_TOKEN) || "",
   }),
 
   getters: {
@@ -27,30 +30,61 @@ export const useAuthStore = defineStore("auth", {
       localStorage.setItem(ACCESS_TOKEN, token);
     },
 
-    setUser(user: User | null) {
-      this.user = user;
+    setTokens(data: LoginResponse) {
+      this.setToken(data.token);
+      this.refreshToken = data.refresh_token;
+      localStorage.setItem(REFRESH_TOKEN, data.refresh_token);
     },
 
-    logout() {
+    clearTokens() {
       this.token = "";
-      this.user = null;
+      this.refreshToken = "";
       localStorage.removeItem(ACCESS_TOKEN);
+      localStorage.removeItem(REFRESH_TOKEN);
+    },
+
+    async refresh(): Promise<boolean> {
+      if (!this.refreshToken) return false;
+
+      try {
+        const {data} = await fetchApi.post<LoginResponse>("/token/refresh", {refresh_token: this.refreshToken}, {skipRefresh: true});
+        this.setTokens(data);
+        return true;
+      } catch (e) {
+        this.clearTokens();
+        return false;
+      }
+    },
+
+    setUser(user: User | null) {
+      this.user = user;
+    },
 
-      useToast().push({title: "Success", message: "Logged out", kind: "success"});
+    async logout(everywhere = false) {
+      if (this.token) {
+        try {
+          await fetchApi.post(everywhere ? "/logout/all" : "/logout", {}, {skipRefresh: true});
+        } catch (e) {
+          // the session is gone either way
+        }
+      }
+
+      this.clearTokens();
+      this.user = null;
 
-      (async () => await router.push("/login"))();
+      await router.push("/login");
     },
 
     async login(username: string, password: string) {
       const {data} = await fetchApi.post<LoginResponse>("/login", {username, password});
-      this.setToken(data.token);
+      this.setTokens(data);
       await this.fetchProfile();
       await router.push("/");
     },
 
     async register(username: string, password: string) {
       const {data} = await fetchApi.post<LoginResponse>("/register", {username, password});
-      this.setToken(data.token);
+      this.setTokens(data);
       await this.fetchProfile();
       await router.push("/");
     },
@@ -65,7 +99,7 @@ export const useAuthStore = defineStore("auth", {
         if (!this.token) return;
         await this.fetchProfile();
       } catch (e) {
-        this.logout();
+        await this.logout();
       }
     },
   },
@@ -0,0 +1,115 @@
+package achievements
+
+// Signal is something a player did that achievements can react to.
+type Signal string
+
+const (
+	SignalBanked  Signal = "banked"
+	SignalFarkle  Signal = "farkle"
+	SignalHotDice Signal = "hot_dice"
+	SignalWon     Signal = "won"
+	SignalLost    Signal = "lost"
+)
+
+// Fact is a signal attributed to a single player.
+type Fact struct {
+	UserID uint
+	Signal Signal
+	Points uint
+}
+
+// Rule moves a player's progress towards an achievement for a fact.
+type Rule func(progress uint, fact Fact) uint
+
+type Definition struct {
+	Slug        string
+	Title       string
+	Description string
+	Goal        uint
+	Rule        Rule
+}
+
+// Catalogue lists every achievement in the order they are shown.
+var Catalogue = []Definition{
+	{
+		Slug:        "first_blood",
+		Title:       "First Blood",
+		Description: "Win your first match",
+		Goal:        1,
+		Rule:        Count(SignalWon),
+	},
+	{
+		Slug:        "lucky_hand",
+		Title:       "Lucky Hand",
+		Description: "Score 1,000+ points in a single round",
+		Goal:        1000,
+		Rule:        Best(SignalBanked),
+	},
+	{
+		Slug:        "hot_hands",
+		Title:       "Hot Hands",
+		Description: "Hit hot dice 10 times",
+		Goal:        10,
+		Rule:        Count(SignalHotDice),
+	},
+	{
+		Slug:        "cold_streak",
+		Title:       "Cold Streak",
+		Description: "Farkle 3 rounds in a row",
+		Goal:        3,
+		Rule:        Streak(SignalFarkle, SignalBanked),
+	},
+	{
+		Slug:        "on_a_roll",
+		Title:       "On a Roll",
+		Description: "Win 3 matches in a row",
+		Goal:        3,
+		Rule:        Streak(SignalWon, SignalLost),
+	},
+	{
+		Slug:        "tavern_legend",
+		Title:       "Tavern Legend",
+		Description: "Win 50 matches",
+		Goal:        50,
+		Rule:        Count(SignalWon),
+	},
+}
+
+// Count counts every occurrence of a signal.
+func Count(signal Signal) Rule {
+	return func(progress uint, fact Fact) uint {
+		if fact.Signal == signal {
+			return progress + 1
+		}
+
+		return progress
+	}
+}
+
+// Streak counts consecutive occurrences of a signal, starting over on any of resets.
+func Streak(signal Signal, resets ...Signal) Rule {
+	return func(progress uint, fact Fact) uint {
+		if fact.Signal == signal {
+			return progress + 1
+		}
+
+		for _, reset := range resets {
+			if fact.Signal == reset {
+				return 0
+			}
+		}
+
+		return progress
+	}
+}
+
+// Best keeps the highest points seen with a signal.
+func Best(signal Signal) Rule {
+	return func(progress uint, fact Fact) uint {
+		if fact.Signal == signal && fact.Points > progress {
+			return fact.Points
+		}
+
+		return progress
+	}
+}
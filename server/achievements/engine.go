@@ -0,0 +1,178 @@
+// Package achievements unlocks catalogue achievements from game events.
+package achievements
+
+import (
+	"app/events"
+	"app/farkle"
+	"app/models"
+	"app/repositories"
+	"log"
+	"time"
+)
+
+const (
+	queueSize = 256
+	// enqueueTimeout is how long a publisher is held back by a full queue
+	// before the event is given up on.
+	enqueueTimeout = 5 * time.Second
+)
+
+// Engine evaluates game events off the publisher's goroutine and persists
+// the progress of every affected player.
+type Engine struct {
+	achievementRepo *repositories.AchievementRepository
+	gameRepo        *repositories.GameRepository
+	bus             *events.Bus
+	queue           chan events.Event
+}
+
+func NewEngine(
+	achievementRepo *repositories.AchievementRepository,
+	gameRepo *repositories.GameRepository,
+	bus *events.Bus,
+) *Engine {
+	engine := &Engine{
+		achievementRepo: achievementRepo,
+		gameRepo:        gameRepo,
+		bus:             bus,
+		queue:           make(chan events.Event, queueSize),
+	}
+
+	bus.Subscribe(engine.enqueue)
+	go engine.run()
+
+	return engine
+}
+
+func (engine *Engine) enqueue(event events.Event) {
+	switch event.Type {
+	case events.Banked, events.Farkle, events.HotDice, events.GameOver:
+	default:
+		return
+	}
+
+	select {
+	case engine.queue <- event:
+		return
+	default:
+	}
+
+	// a burst of events waits for the worker to catch up rather than losing
+	// progress that nothing would ever recount
+	timer := time.NewTimer(enqueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case engine.queue <- event:
+	case <-timer.C:
+		log.Println("achievements: queue stayed full, dropping event", event.Type, "of game", event.GameCode)
+	}
+}
+
+func (engine *Engine) run() {
+	for event := range engine.queue {
+		facts, err := engine.facts(event)
+
+		if err != nil {
+			log.Println("achievements: failed to read event:", err)
+			continue
+		}
+
+		for _, fact := range facts {
+			if err := engine.apply(event, fact); err != nil {
+				log.Println("achievements: failed to apply fact:", err)
+			}
+		}
+	}
+}
+
+// facts attributes an event to the players it concerns. A finished game
+// is a win for the winner and a loss for everyone else.
+func (engine *Engine) facts(event events.Event) ([]Fact, error) {
+	switch event.Type {
+	case events.Banked:
+		outcome, _ := event.Data.(farkle.Outcome)
+
+		return []Fact{{UserID: event.UserID, Signal: SignalBanked, Points: outcome.Banked}}, nil
+	case events.Farkle:
+		return []Fact{{UserID: event.UserID, Signal: SignalFarkle}}, nil
+	case events.HotDice:
+		return []Fact{{UserID: event.UserID, Signal: SignalHotDice}}, nil
+	case events.GameOver:
+		players, err := engine.gameRepo.FindPlayers(event.GameID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		facts := make([]Fact, 0, len(players))
+
+		for _, p := range players {
+			signal := SignalLost
+
+			if p.IsWinner {
+				signal = SignalWon
+			}
+
+			facts = append(facts, Fact{UserID: p.UserID, Signal: signal})
+		}
+
+		return facts, nil
+	}
+
+	return nil, nil
+}
+
+func (engine *Engine) apply(event events.Event, fact Fact) error {
+	current, err := engine.achievementRepo.FindByUser(fact.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	bySlug := make(map[string]models.UserAchievement, len(current))
+
+	for _, a := range current {
+		bySlug[a.Slug] = a
+	}
+
+	for _, definition := range Catalogue {
+		achievement, ok := bySlug[definition.Slug]
+
+		if !ok {
+			achievement = models.UserAchievement{UserID: fact.UserID, Slug: definition.Slug}
+		}
+
+		if achievement.IsUnlocked() {
+			continue
+		}
+
+		progress := definition.Rule(achievement.Progress, fact)
+
+		if progress == achievement.Progress {
+			continue
+		}
+
+		achievement.Progress = min(progress, definition.Goal)
+
+		if achievement.Progress == definition.Goal {
+			achievement.UnlockedAt = time.Now()
+		}
+
+		if err := engine.achievementRepo.Save(&achievement); err != nil {
+			return err
+		}
+
+		if achievement.IsUnlocked() {
+			engine.bus.Publish(events.Event{
+				Type:     events.AchievementUnlocked,
+				GameID:   event.GameID,
+				GameCode: event.GameCode,
+				UserID:   fact.UserID,
+				Data:     map[string]any{"slug": definition.Slug, "title": definition.Title},
+			})
+		}
+	}
+
+	return nil
+}
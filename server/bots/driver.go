@@ -0,0 +1,241 @@
+package bots
+
+import (
+	"app/events"
+	"app/farkle"
+	"app/models"
+	"app/repositories"
+	"app/services"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// DefaultThinkDelay is how long a bot pauses before each action when no
+// delay is configured.
+const DefaultThinkDelay = 1500 * time.Millisecond
+
+// maxFailures is how many times in a row a move is retried before the bot
+// waits for the next event of its game. Every retry waits retryBackoff
+// longer than the one before.
+const (
+	maxFailures  = 3
+	retryBackoff = 500 * time.Millisecond
+)
+
+// Driver watches game events and plays the turns of bot players through the
+// same turn service humans use.
+type Driver struct {
+	turnService *services.TurnService
+	userRepo    *repositories.UserRepository
+	gameRepo    *repositories.GameRepository
+	thinkDelay  time.Duration
+
+	mu      sync.Mutex
+	bots    map[uint]*models.User
+	pending map[string]bool
+}
+
+func NewDriver(
+	turnService *services.TurnService,
+	userRepo *repositories.UserRepository,
+	gameRepo *repositories.GameRepository,
+	bus *events.Bus,
+	thinkDelay time.Duration,
+) *Driver {
+	driver := &Driver{
+		turnService: turnService,
+		userRepo:    userRepo,
+		gameRepo:    gameRepo,
+		thinkDelay:  thinkDelay,
+		bots:        make(map[uint]*models.User),
+		pending:     make(map[string]bool),
+	}
+
+	bus.Subscribe(driver.observe)
+
+	return driver
+}
+
+// Start schedules a move in the games that were on a bot's turn when the
+// server last stopped.
+func (driver *Driver) Start() error {
+	games, err := driver.gameRepo.FindOnBotTurn()
+
+	if err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		driver.schedule(game.Code, 0)
+	}
+
+	return nil
+}
+
+// observe schedules a move whenever the event's player is a bot. Finding out
+// reads the database, so it happens off the publisher's goroutine.
+func (driver *Driver) observe(event events.Event) {
+	switch event.Type {
+	case events.GameStarted, events.TurnChanged, events.Rolled, events.SetAside:
+	default:
+		return
+	}
+
+	go func() {
+		if driver.bot(event.UserID) != nil {
+			driver.schedule(event.GameCode, 0)
+		}
+	}()
+}
+
+// schedule makes the next move in the game after a think delay, with at most
+// one move waiting or running per game. The move is made by whichever bot is
+// on turn when the delay is over, so a turn passing from one bot to another
+// is played by the right one. Events arriving during a move are covered by
+// scheduling again once a bot has acted. A move that fails is tried again a
+// few times.
+func (driver *Driver) schedule(code string, failures int) {
+	driver.mu.Lock()
+	defer driver.mu.Unlock()
+
+	if driver.pending[code] {
+		return
+	}
+
+	driver.pending[code] = true
+
+	delay := driver.think() + time.Duration(failures)*retryBackoff
+
+	time.AfterFunc(delay, func() {
+		played, err := driver.move(code)
+
+		driver.mu.Lock()
+		delete(driver.pending, code)
+		driver.mu.Unlock()
+
+		if err != nil {
+			log.Println("bots: failed to play:", err)
+
+			if failures < maxFailures {
+				driver.schedule(code, failures+1)
+			}
+
+			return
+		}
+
+		if played {
+			driver.schedule(code, 0)
+		}
+	})
+}
+
+// move plays a single action for the bot whose turn it is, reporting whether
+// there was one.
+func (driver *Driver) move(code string) (bool, error) {
+	game, err := driver.gameRepo.FindByCode(code)
+
+	if err != nil || game.State == nil || game.IsFinished() || game.IsCancelled() {
+		return false, err
+	}
+
+	bot := driver.bot(game.State.CurrentUserID)
+
+	if bot == nil {
+		return false, nil
+	}
+
+	return true, driver.play(bot, code)
+}
+
+// bot returns the user when it is a bot, remembering the answer either way.
+func (driver *Driver) bot(userId uint) *models.User {
+	if userId == 0 {
+		return nil
+	}
+
+	driver.mu.Lock()
+	bot, known := driver.bots[userId]
+	driver.mu.Unlock()
+
+	if known {
+		return bot
+	}
+
+	user, err := driver.userRepo.FindById(userId)
+
+	if err != nil {
+		return nil
+	}
+
+	if !user.IsBot {
+		user = nil
+	}
+
+	driver.mu.Lock()
+	driver.bots[userId] = user
+	driver.mu.Unlock()
+
+	return user
+}
+
+// think is the delay before the next action, with some jitter so bots do
+// not move like clockwork.
+func (driver *Driver) think() time.Duration {
+	return driver.thinkDelay/2 + rand.N(driver.thinkDelay+1)
+}
+
+// play takes a single action when it is the bot's turn.
+func (driver *Driver) play(bot *models.User, code string) error {
+	snapshot, err := driver.turnService.GetState(bot, code)
+
+	if err != nil {
+		return err
+	}
+
+	state := snapshot.Game.State
+
+	if state == nil || snapshot.Game.IsFinished() || state.CurrentUserID != bot.ID {
+		return nil
+	}
+
+	strategy, ok := Lookup(bot.BotStrategy)
+
+	if !ok {
+		strategy = Cautious{}
+	}
+
+	turn := Turn{
+		DiceLeft:      int(state.DiceLeft),
+		TurnScore:     state.TurnScore,
+		WinningPoints: snapshot.Game.WinningPoints,
+		Rules:         farkle.Rules(snapshot.Game.Rules),
+	}
+
+	for _, p := range snapshot.Players {
+		switch {
+		case p.UserID == bot.ID:
+			turn.Total = p.Score
+		case state.LastRoundBy != 0 && !p.HasForfeited():
+			turn.Beat = max(turn.Beat, p.Score)
+		}
+	}
+
+	if farkle.Phase(state.Phase) == farkle.PhaseSetAside {
+		turn.Dice = state.Dice
+	}
+
+	decision := strategy.Decide(turn)
+
+	switch {
+	case decision.Bank:
+		_, err = driver.turnService.Bank(bot, code, decision.Keep)
+	case len(decision.Keep) > 0:
+		_, err = driver.turnService.SetAside(bot, code, decision.Keep)
+	default:
+		_, err = driver.turnService.Roll(bot, code, "")
+	}
+
+	return err
+}
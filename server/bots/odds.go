@@ -0,0 +1,68 @@
+package bots
+
+import (
+	"app/farkle"
+	"sync"
+)
+
+// throwOdds are the chance to farkle and the average best score of a
+// successful throw, indexed by the number of dice thrown. They are worked
+// out under the standard rules, which is close enough for any house.
+type throwOdds struct {
+	farkle [farkle.DiceCount + 1]float64
+	gain   [farkle.DiceCount + 1]float64
+}
+
+var odds = sync.OnceValue(func() throwOdds {
+	var table throwOdds
+
+	for n := 1; n <= farkle.DiceCount; n++ {
+		var throws, farkles int
+		var total float64
+
+		eachThrow(n, func(dice []int) {
+			throws++
+			best := highest(options(dice, farkle.StandardRules))
+
+			if best.score == 0 {
+				farkles++
+				return
+			}
+
+			total += float64(best.score)
+		})
+
+		table.farkle[n] = float64(farkles) / float64(throws)
+		table.gain[n] = total / float64(throws-farkles)
+	}
+
+	return table
+})
+
+// continueValue is the expected turn score of rolling n more dice once
+// and banking whatever comes of it.
+func continueValue(score uint, n int) float64 {
+	table := odds()
+
+	return (1 - table.farkle[n]) * (float64(score) + table.gain[n])
+}
+
+// eachThrow calls fn with every possible throw of n dice.
+func eachThrow(n int, fn func(dice []int)) {
+	dice := make([]int, n)
+
+	var walk func(i int)
+	walk = func(i int) {
+		if i == n {
+			fn(dice)
+			return
+		}
+
+		for face := farkle.MinFace; face <= farkle.MaxFace; face++ {
+			dice[i] = face
+			walk(i + 1)
+		}
+	}
+
+	walk(0)
+}
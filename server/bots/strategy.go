@@ -0,0 +1,183 @@
+// Package bots plays games on behalf of computer opponents.
+package bots
+
+import (
+	"app/farkle"
+	"app/http/inputs"
+	"sort"
+)
+
+// Turn is what a bot knows when it is asked to act. Dice is the last throw
+// still waiting to be set aside, or empty when the bot is about to roll.
+// Beat is the total to beat in the last round of a catch-up game.
+type Turn struct {
+	Dice          []int
+	DiceLeft      int
+	TurnScore     uint
+	Total         uint
+	WinningPoints uint
+	Rules         farkle.Rules
+	Beat          uint
+}
+
+// Decision is the scoring dice a bot keeps from the throw and whether it
+// banks afterwards instead of rolling again.
+type Decision struct {
+	Keep []int
+	Bank bool
+}
+
+type Strategy interface {
+	Decide(turn Turn) Decision
+}
+
+var strategies = map[string]Strategy{
+	inputs.StrategyCautious: Cautious{},
+	inputs.StrategyGreedy:   Greedy{},
+	inputs.StrategyOptimal:  Optimal{},
+}
+
+func Lookup(name string) (Strategy, bool) {
+	strategy, ok := strategies[name]
+
+	return strategy, ok
+}
+
+// Cautious keeps every scoring die and banks as soon as the turn is worth something.
+type Cautious struct{}
+
+func (Cautious) Decide(turn Turn) Decision {
+	return decide(turn, highest, func(score uint, left int) bool {
+		return score >= 350 || left <= 2
+	})
+}
+
+// Greedy keeps every scoring die and presses on until the turn is worth 1000.
+type Greedy struct{}
+
+func (Greedy) Decide(turn Turn) Decision {
+	return decide(turn, highest, func(score uint, _ int) bool {
+		return score >= 1000
+	})
+}
+
+// Optimal picks the dice and the bank decision with the best expected turn
+// score, looking one throw ahead.
+type Optimal struct{}
+
+func (Optimal) Decide(turn Turn) Decision {
+	return decide(turn, func(opts []option) option {
+		var best option
+		bestValue := -1.0
+
+		for _, o := range opts {
+			score := turn.TurnScore + o.score
+			value := max(float64(score), continueValue(score, o.left))
+
+			if value > bestValue {
+				best, bestValue = o, value
+			}
+		}
+
+		return best
+	}, func(score uint, left int) bool {
+		return float64(score) >= continueValue(score, left)
+	})
+}
+
+// option is a scoring selection from a throw and the dice left to roll after it.
+type option struct {
+	keep  []int
+	score uint
+	left  int
+}
+
+// decide keeps the option chosen by pick and banks when the rule says so,
+// or whenever banking wins the game. It never banks below the opening
+// score, and in the last round it banks only once the lead is taken.
+func decide(turn Turn, pick func([]option) option, bank func(score uint, left int) bool) Decision {
+	score, left := turn.TurnScore, turn.DiceLeft
+	var keep []int
+
+	if len(turn.Dice) > 0 {
+		chosen := pick(options(turn.Dice, turn.Rules))
+		keep, score, left = chosen.keep, score+chosen.score, chosen.left
+	}
+
+	if score == 0 || turn.Total == 0 && score < turn.Rules.OpeningScore {
+		return Decision{Keep: keep}
+	}
+
+	if turn.Beat > 0 {
+		return Decision{Keep: keep, Bank: turn.Total+score > turn.Beat}
+	}
+
+	return Decision{
+		Keep: keep,
+		Bank: turn.Total+score >= turn.WinningPoints || bank(score, left),
+	}
+}
+
+// highest is the option worth the most points.
+func highest(opts []option) option {
+	var best option
+
+	for _, o := range opts {
+		if o.score > best.score {
+			best = o
+		}
+	}
+
+	return best
+}
+
+// options lists every distinct scoring selection from a throw. Setting aside
+// every die is hot dice and leaves all six to roll.
+func options(dice []int, rules farkle.Rules) []option {
+	seen := map[string]bool{}
+	var opts []option
+
+	for mask := 1; mask < 1<<len(dice); mask++ {
+		var keep []int
+
+		for i, d := range dice {
+			if mask&(1<<i) != 0 {
+				keep = append(keep, d)
+			}
+		}
+
+		sort.Ints(keep)
+		key := string(intsKey(keep))
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		result := rules.Evaluate(keep)
+
+		if !result.Valid {
+			continue
+		}
+
+		left := len(dice) - len(keep)
+
+		if left == 0 {
+			left = farkle.DiceCount
+		}
+
+		opts = append(opts, option{keep: keep, score: result.Score, left: left})
+	}
+
+	return opts
+}
+
+func intsKey(values []int) []byte {
+	key := make([]byte, len(values))
+
+	for i, v := range values {
+		key[i] = byte(v)
+	}
+
+	return key
+}
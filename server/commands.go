@@ -0,0 +1,122 @@
+package main
+
+import (
+	"app/config"
+	"app/database"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const usage = `usage:
+  app [flags]                       start the server
+  app [flags] migrate up            apply every pending migration
+  app [flags] migrate down          roll back the last applied migration
+  app [flags] migrate status        list migrations and whether they are applied
+  app [flags] migrate create NAME   add an empty up and down migration
+  app [flags] seed                  write the reference data`
+
+// runCommand runs a maintenance command instead of the server.
+func runCommand(cfg *config.Config, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(cfg, args[1:])
+	case "seed":
+		db, err := database.Connect(cfg)
+
+		if err != nil {
+			return err
+		}
+
+		if err := database.Seed(db); err != nil {
+			return err
+		}
+
+		fmt.Println("Seeded reference data")
+
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	if args[0] == "create" {
+		if len(args) < 2 {
+			return errors.New("migrate create needs a name")
+		}
+
+		paths, err := database.CreateMigration(database.MigrationsDir, strings.Join(args[1:], "_"))
+
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+
+		return nil
+	}
+
+	db, err := database.Connect(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	migrator, err := database.NewMigrator(db)
+
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+
+		for _, migration := range applied {
+			fmt.Printf("Applied %04d_%s\n", migration.Version, migration.Name)
+		}
+
+		if err == nil && len(applied) == 0 {
+			fmt.Println("Nothing to migrate")
+		}
+
+		return err
+	case "down":
+		migration, err := migrator.Down()
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Rolled back %04d_%s\n", migration.Version, migration.Name)
+
+		return nil
+	case "status":
+		statuses, err := migrator.Status()
+
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			applied := "pending"
+
+			if status.IsApplied() {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, applied)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], usage)
+	}
+}
@@ -1,19 +1,126 @@
+// Package config loads the server settings once at startup.
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-func Config(key string) string {
-	// load .env file
-	err := godotenv.Load(".env")
+const (
+	DefaultEnvFile     = ".env"
+	DefaultDBName      = "fiber"
+	DefaultCORSOrigins = "http://localhost:5173"
+)
+
+// Config holds every setting of the server. Values come from the
+// environment, which a .env file fills in, and command line flags take
+// precedence over both.
+type Config struct {
+	APIPort   string
+	JWTSecret string
+	DBName    string
+	// BotThinkDelay is how long bots pause before acting, zero for not at
+	// all; nil keeps the bots' default.
+	BotThinkDelay *time.Duration
+	// DisconnectGrace is how long a player may stay disconnected from a
+	// timed game before forfeiting it; zero keeps the default.
+	DisconnectGrace time.Duration
+	CORSOrigins     []string
+	// Args are the command line arguments left after the flags, such as a
+	// maintenance command to run instead of the server.
+	Args []string
+}
+
+// Load reads the configuration. args are the command line arguments
+// without the program name. The server must call Validate before it starts;
+// maintenance commands only need the database settings.
+func Load(args []string) (*Config, error) {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	envFile := flags.String("env", DefaultEnvFile, "path to the .env file")
+	port := flags.String("port", "", "port to listen on, overrides API_PORT")
+	dbName := flags.String("db", "", "database name, overrides DB_NAME")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if err := godotenv.Load(*envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("config: failed to read %s: %w", *envFile, err)
+	}
+
+	cfg := &Config{
+		APIPort:     firstOf(*port, os.Getenv("API_PORT")),
+		JWTSecret:   os.Getenv("JWT_SECRET"),
+		DBName:      firstOf(*dbName, os.Getenv("DB_NAME"), DefaultDBName),
+		CORSOrigins: splitList(firstOf(os.Getenv("CORS_ORIGINS"), DefaultCORSOrigins)),
+		Args:        flags.Args(),
+	}
+
+	if value := os.Getenv("BOT_THINK_DELAY"); value != "" {
+		delay, err := time.ParseDuration(value)
+
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("config: BOT_THINK_DELAY must be a duration such as 1.5s, got %q", value)
+		}
+
+		cfg.BotThinkDelay = &delay
+	}
+
+	if value := os.Getenv("DISCONNECT_GRACE"); value != "" {
+		grace, err := time.ParseDuration(value)
+
+		if err != nil || grace < 0 {
+			return nil, fmt.Errorf("config: DISCONNECT_GRACE must be a duration such as 60s, got %q", value)
+		}
+
+		cfg.DisconnectGrace = grace
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.JWTSecret == "" {
+		return errors.New("config: JWT_SECRET is required")
+	}
 
-	if err != nil {
-		fmt.Println(err)
-		fmt.Println("Error loading .env file")
+	if cfg.APIPort == "" {
+		return errors.New("config: API_PORT is required")
 	}
-	return os.Getenv(key)
+
+	if port, err := strconv.Atoi(cfg.APIPort); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("config: API_PORT must be a port number, got %q", cfg.APIPort)
+	}
+
+	return nil
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func splitList(value string) []string {
+	var list []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
 }
@@ -0,0 +1,172 @@
+// Package container wires the application together. Every dependency is
+// built once from the configuration and database handed to New, so any
+// layer can be assembled against its own database, such as an in-memory
+// SQLite one, without global state.
+package container
+
+import (
+	"app/achievements"
+	"app/bots"
+	"app/config"
+	"app/events"
+	"app/http/handlers"
+	"app/http/middlewares"
+	"app/ratelimit"
+	"app/realtime"
+	"app/repositories"
+	"app/services"
+	"app/timers"
+
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+)
+
+type Repositories struct {
+	Achievements *repositories.AchievementRepository
+	Audit        *repositories.AuditRepository
+	Balances     *repositories.BalanceRepository
+	Currencies   *repositories.CurrencyRepository
+	Friends      *repositories.FriendRepository
+	Games        *repositories.GameRepository
+	Inventory    *repositories.InventoryRepository
+	Leaderboard  *repositories.LeaderboardRepository
+	Ledger       *repositories.LedgerRepository
+	Sessions     *repositories.SessionRepository
+	Users        *repositories.UserRepository
+	Transactor   *repositories.Transactor
+}
+
+type Services struct {
+	Accounts     *services.AccountService
+	Achievements *services.AchievementService
+	Currencies   *services.CurrencyService
+	Fairness     *services.FairnessService
+	Friends      *services.FriendService
+	Games        *services.GameService
+	Inventory    *services.InventoryService
+	Leaderboard  *services.LeaderboardService
+	Ledger       *services.LedgerService
+	Lobby        *services.LobbyService
+	LoginGuard   *services.LoginGuard
+	Matches      *services.MatchService
+	Sessions     *services.SessionService
+	Turns        *services.TurnService
+}
+
+type Handlers struct {
+	Accounts     *handlers.AccountHandler
+	Achievements *handlers.AchievementHandler
+	Auth         *handlers.AuthHandler
+	Currencies   *handlers.CurrencyHandler
+	Fairness     *handlers.FairnessHandler
+	Friends      *handlers.FriendHandler
+	Games        *handlers.GameHandler
+	Inventory    *handlers.InventoryHandler
+	Leaderboard  *handlers.LeaderboardHandler
+	Ledger       *handlers.LedgerHandler
+	Lobby        *handlers.LobbyHandler
+	Matches      *handlers.MatchHandler
+	Socket       *handlers.SocketHandler
+	Turns        *handlers.TurnHandler
+}
+
+type Container struct {
+	Config       *config.Config
+	DB           *gorm.DB
+	Bus          *events.Bus
+	Repositories Repositories
+	Services     Services
+	Handlers     Handlers
+	// Protected and ProtectedSocket guard routes that need a signed-in user.
+	Protected       fiber.Handler
+	ProtectedSocket fiber.Handler
+	Hub             *realtime.Hub
+	Bots            *bots.Driver
+	Achievements    *achievements.Engine
+	Timers          *timers.Scheduler
+}
+
+func New(cfg *config.Config, db *gorm.DB) *Container {
+	c := &Container{Config: cfg, DB: db, Bus: events.NewBus()}
+
+	c.Repositories = newRepositories(db)
+	c.Services = newServices(c.Repositories, c.Bus, cfg)
+	c.Hub = realtime.NewHub(c.Bus)
+	c.Handlers = newHandlers(c.Services, c.Hub)
+
+	c.Protected = middlewares.Protected(cfg.JWTSecret, c.Services.Sessions)
+	c.ProtectedSocket = middlewares.ProtectedSocket(cfg.JWTSecret, c.Services.Sessions)
+
+	thinkDelay := bots.DefaultThinkDelay
+
+	if cfg.BotThinkDelay != nil {
+		thinkDelay = *cfg.BotThinkDelay
+	}
+
+	c.Bots = bots.NewDriver(c.Services.Turns, c.Repositories.Users, c.Repositories.Games, c.Bus, thinkDelay)
+	grace := cfg.DisconnectGrace
+
+	if grace == 0 {
+		grace = timers.DefaultGrace
+	}
+
+	c.Timers = timers.NewScheduler(c.Services.Turns, c.Repositories.Games, c.Bus, grace)
+	c.Achievements = achievements.NewEngine(c.Repositories.Achievements, c.Repositories.Games, c.Bus)
+
+	return c
+}
+
+func newRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Achievements: repositories.NewAchievementRepository(db),
+		Audit:        repositories.NewAuditRepository(db),
+		Balances:     repositories.NewBalanceRepository(db),
+		Currencies:   repositories.NewCurrencyRepository(db),
+		Friends:      repositories.NewFriendRepository(db),
+		Games:        repositories.NewGameRepository(db),
+		Inventory:    repositories.NewInventoryRepository(db),
+		Leaderboard:  repositories.NewLeaderboardRepository(db),
+		Ledger:       repositories.NewLedgerRepository(db),
+		Sessions:     repositories.NewSessionRepository(db),
+		Users:        repositories.NewUserRepository(db),
+		Transactor:   repositories.NewTransactor(db),
+	}
+}
+
+func newServices(repos Repositories, bus *events.Bus, cfg *config.Config) Services {
+	return Services{
+		Accounts:     services.NewAccountService(repos.Currencies, repos.Users, repos.Transactor, bus),
+		Achievements: services.NewAchievementService(repos.Achievements),
+		Currencies:   services.NewCurrencyService(repos.Currencies),
+		Fairness:     services.NewFairnessService(repos.Games),
+		Friends:      services.NewFriendService(repos.Friends, repos.Users, repos.Transactor),
+		Games:        services.NewGameService(repos.Balances, repos.Currencies, repos.Games, repos.Users, repos.Transactor, bus),
+		Inventory:    services.NewInventoryService(repos.Inventory, repos.Currencies, repos.Transactor),
+		Leaderboard:  services.NewLeaderboardService(repos.Leaderboard),
+		Ledger:       services.NewLedgerService(repos.Ledger),
+		Lobby:        services.NewLobbyService(repos.Games),
+		LoginGuard:   services.NewLoginGuard(ratelimit.NewMemoryStore(), repos.Audit),
+		Matches:      services.NewMatchService(repos.Games, repos.Users),
+		Sessions:     services.NewSessionService(repos.Sessions, repos.Users, repos.Transactor, cfg.JWTSecret),
+		Turns:        services.NewTurnService(repos.Games, repos.Transactor, bus),
+	}
+}
+
+func newHandlers(svc Services, hub *realtime.Hub) Handlers {
+	return Handlers{
+		Accounts:     handlers.NewAccountHandler(svc.Accounts),
+		Achievements: handlers.NewAchievementHandler(svc.Achievements),
+		Auth:         handlers.NewAuthHandler(svc.Sessions, svc.Accounts, svc.LoginGuard),
+		Currencies:   handlers.NewCurrencyHandler(svc.Currencies),
+		Fairness:     handlers.NewFairnessHandler(svc.Fairness),
+		Friends:      handlers.NewFriendHandler(svc.Friends),
+		Games:        handlers.NewGameHandler(svc.Games, hub),
+		Inventory:    handlers.NewInventoryHandler(svc.Inventory),
+		Leaderboard:  handlers.NewLeaderboardHandler(svc.Leaderboard),
+		Ledger:       handlers.NewLedgerHandler(svc.Ledger),
+		Lobby:        handlers.NewLobbyHandler(svc.Lobby),
+		Matches:      handlers.NewMatchHandler(svc.Matches),
+		Socket:       handlers.NewSocketHandler(svc.Games, svc.Turns, hub),
+		Turns:        handlers.NewTurnHandler(svc.Turns, hub),
+	}
+}
@@ -11,49 +11,65 @@ import (
 	"gorm.io/gorm"
 )
 
-func Connect() {
-	var err error
+// busyTimeout is how many milliseconds a connection waits for another one
+// to finish writing before giving up with "database is locked".
+const busyTimeout = 5000
 
-	dbName := config.Config("DB_NAME")
+// Connect opens the configured database file. Transactions take the write
+// lock as they begin, as SQLite gives up at once, without waiting, on a
+// transaction that read first and then finds another one writing.
+func Connect(cfg *config.Config) (*gorm.DB, error) {
+	return Open(fmt.Sprintf("%s.db?_busy_timeout=%d&_txlock=immediate", cfg.DBName, busyTimeout))
+}
 
-	DB, err = gorm.Open(sqlite.Open(dbName+".db"), &gorm.Config{})
+// Open opens the SQLite database at dsn. The schema is not touched; run
+// the migrations first. Every connection of the pool opens dsn on its own,
+// so a plain "file::memory:" gives each one an empty database of its own;
+// tests pass a shared cache, like "file:name?mode=memory&cache=shared", and
+// call Migrate.
+func Open(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 
 	if err != nil {
-		panic("failed to connect database")
+		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
 	fmt.Println("Connected to database")
 
-	migrateAll()
-	setupRelations()
-	SeedAll()
+	if err := setupRelations(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
 }
 
-func migrateAll() {
-	err := DB.AutoMigrate(
-		&models.User{},
-		&models.Currency{},
-		&models.Balance{},
-		&models.GameUser{},
-	)
+// Migrate brings the schema up to date and seeds the reference data.
+func Migrate(db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
 
 	if err != nil {
-		panic(fmt.Errorf("failed to migrate database: %w", err))
+		return err
 	}
 
-	fmt.Println("Database migrated successfully")
+	if _, err := migrator.Up(); err != nil {
+		return err
+	}
+
+	return Seed(db)
 }
 
-func setupRelations() {
-	err := DB.SetupJoinTable(&models.Game{}, "Users", &models.GameUser{})
+func setupRelations(db *gorm.DB) error {
+	err := db.SetupJoinTable(&models.Game{}, "Users", &models.GameUser{})
 
 	if err != nil {
-		panic(fmt.Errorf("failed to setup relations: %w", err))
+		return fmt.Errorf("failed to setup relations: %w", err)
 	}
 
-	err = DB.SetupJoinTable(&models.User{}, "Games", &models.GameUser{})
+	err = db.SetupJoinTable(&models.User{}, "Games", &models.GameUser{})
 
 	if err != nil {
-		panic(fmt.Errorf("failed to setup relations: %w", err))
+		return fmt.Errorf("failed to setup relations: %w", err)
 	}
+
+	return nil
 }
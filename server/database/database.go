@@ -1,7 +0,0 @@
-package database
-
-import (
-	"gorm.io/gorm"
-)
-
-var DB *gorm.DB
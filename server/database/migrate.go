@@ -0,0 +1,326 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MigrationsDir is where migration files live, relative to the server root.
+const MigrationsDir = "database/migrations"
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	ErrNoMigrations      = errors.New("no migration to roll back")
+	ErrPendingMigrations = errors.New("database has pending migrations, run `migrate up`")
+
+	migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	slugInvalid   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Migration is a numbered pair of SQL scripts that move the schema one
+// version forward and back.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+const schemaTableSql = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version integer PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	applied_at datetime NOT NULL
+)`
+
+// SchemaMigration is a row of the table tracking applied migrations.
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey; autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255); not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// MigrationStatus tells whether a migration has been applied and when.
+type MigrationStatus struct {
+	Migration
+	AppliedAt time.Time
+}
+
+func (status MigrationStatus) IsApplied() bool {
+	return !status.AppliedAt.IsZero()
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator reads the migrations embedded in the binary.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(migrationFiles, "migrations")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Exec(schemaTableSql).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema table: %w", err)
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads every NNNN_name.up.sql and NNNN_name.down.sql pair
+// in dir, ordered by version.
+func LoadMigrations(files fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+
+	for _, entry := range entries {
+		parts := migrationName.FindStringSubmatch(entry.Name())
+
+		if parts == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseUint(parts[1], 10, 64)
+		body, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: parts[2]}
+			byVersion[uint(version)] = migration
+		}
+
+		if migration.Name != parts[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, parts[2])
+		}
+
+		if parts[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Status lists every known migration with the time it was applied.
+func (migrator *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := migrator.applied()
+
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]MigrationStatus, len(migrator.migrations))
+
+	for i, migration := range migrator.migrations {
+		list[i] = MigrationStatus{Migration: migration, AppliedAt: applied[migration.Version].AppliedAt}
+	}
+
+	return list, nil
+}
+
+// Pending returns the migrations that have not been applied yet.
+func (migrator *Migrator) Pending() ([]Migration, error) {
+	statuses, err := migrator.Status()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+
+	for _, status := range statuses {
+		if !status.IsApplied() {
+			pending = append(pending, status.Migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction.
+func (migrator *Migrator) Up() ([]Migration, error) {
+	pending, err := migrator.Pending()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range pending {
+		err := migrator.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+
+		if err != nil {
+			return pending[:i], fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return pending, nil
+}
+
+// Down rolls back the most recently applied migration.
+func (migrator *Migrator) Down() (*Migration, error) {
+	var last SchemaMigration
+
+	err := migrator.db.Order("version DESC").Limit(1).Find(&last).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if last.Version == 0 {
+		return nil, ErrNoMigrations
+	}
+
+	var migration *Migration
+
+	for i := range migrator.migrations {
+		if migrator.migrations[i].Version == last.Version {
+			migration = &migrator.migrations[i]
+		}
+	}
+
+	if migration == nil {
+		return nil, fmt.Errorf("migration %d_%s is applied but its files are missing", last.Version, last.Name)
+	}
+
+	err = migrator.db.Transaction(func(tx *gorm.DB) error {
+		if migration.Down != "" {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	return migration, nil
+}
+
+func (migrator *Migrator) applied() (map[uint]SchemaMigration, error) {
+	var rows []SchemaMigration
+
+	if err := migrator.db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]SchemaMigration, len(rows))
+
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// EnsureMigrated refuses to serve a database with pending migrations.
+func EnsureMigrated(db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
+
+	if err != nil {
+		return err
+	}
+
+	pending, err := migrator.Pending()
+
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%w (%d pending)", ErrPendingMigrations, len(pending))
+	}
+
+	return nil
+}
+
+// CreateMigration writes an empty up and down file for the next version in
+// dir and returns their paths.
+func CreateMigration(dir, name string) ([]string, error) {
+	slug := strings.Trim(slugInvalid.ReplaceAllString(strings.ToLower(name), "_"), "_")
+
+	if slug == "" {
+		return nil, errors.New("migration name is required")
+	}
+
+	existing, err := LoadMigrations(os.DirFS(dir), ".")
+
+	if err != nil {
+		return nil, err
+	}
+
+	var version uint = 1
+
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	var paths []string
+
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%04d_%s.%s.sql", version, slug, direction))
+		body := fmt.Sprintf("-- %04d %s (%s)\n", version, slug, direction)
+
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS `audit_logs`;
+DROP TABLE IF EXISTS `sessions`;
+DROP TABLE IF EXISTS `inventory_items`;
+DROP TABLE IF EXISTS `user_achievements`;
+DROP TABLE IF EXISTS `friend_requests`;
+DROP TABLE IF EXISTS `ledger_entries`;
+DROP TABLE IF EXISTS `game_rolls`;
+DROP TABLE IF EXISTS `game_states`;
+DROP TABLE IF EXISTS `balances`;
+DROP TABLE IF EXISTS `user_friends`;
+DROP TABLE IF EXISTS `game_user`;
+DROP TABLE IF EXISTS `games`;
+DROP TABLE IF EXISTS `currencies`;
+DROP TABLE IF EXISTS `users`;
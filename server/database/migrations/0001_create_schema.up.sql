@@ -0,0 +1,208 @@
+-- Baseline schema. Databases created by AutoMigrate already have these
+-- tables, so every statement is guarded and applying it is a no-op there.
+
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `username` varchar(255) NOT NULL,
+    `password` text NOT NULL,
+    `is_bot` numeric NOT NULL DEFAULT false,
+    `bot_strategy` varchar(32),
+    `deleted_at` datetime,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_users_username` ON `users`(`username`);
+
+CREATE TABLE IF NOT EXISTS `currencies` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `slug` varchar(255) NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_currencies_slug` ON `currencies`(`slug`);
+
+CREATE TABLE IF NOT EXISTS `games` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `code` varchar(255) NOT NULL,
+    `currency_id` integer NOT NULL,
+    `creator_id` integer NOT NULL,
+    `bet` integer NOT NULL,
+    `winning_points` integer NOT NULL,
+    `join_type` varchar(255) NOT NULL DEFAULT 'anyone',
+    `max_players` integer NOT NULL DEFAULT 2,
+    `server_seed` varchar(64),
+    `server_seed_hash` varchar(64),
+    `started_at` datetime,
+    `finished_at` datetime,
+    `cancelled_at` datetime,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_games_currency` FOREIGN KEY (`currency_id`) REFERENCES `currencies`(`id`),
+    CONSTRAINT `fk_games_creator` FOREIGN KEY (`creator_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_games_join_type` ON `games`(`join_type`);
+CREATE INDEX IF NOT EXISTS `idx_games_creator_id` ON `games`(`creator_id`);
+CREATE INDEX IF NOT EXISTS `idx_games_currency_id` ON `games`(`currency_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_games_code` ON `games`(`code`);
+
+CREATE TABLE IF NOT EXISTS `game_user` (
+    `user_id` integer NOT NULL,
+    `game_id` integer NOT NULL,
+    `is_winner` boolean NOT NULL DEFAULT false,
+    `score` integer NOT NULL DEFAULT 0,
+    `best_turn` integer NOT NULL DEFAULT 0,
+    `client_seed` varchar(64),
+    `loadout` text,
+    `badge` varchar(32),
+    `badge_used` numeric NOT NULL DEFAULT false,
+    `stake` integer NOT NULL DEFAULT 0,
+    `payout` integer NOT NULL DEFAULT 0,
+    `created_at` datetime,
+    PRIMARY KEY (`user_id`,`game_id`),
+    CONSTRAINT `fk_game_user_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_games_participants` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_game_user_is_winner` ON `game_user`(`is_winner`);
+CREATE INDEX IF NOT EXISTS `idx_game_user_game_id` ON `game_user`(`game_id`);
+CREATE INDEX IF NOT EXISTS `idx_game_user_user_id` ON `game_user`(`user_id`);
+
+CREATE TABLE IF NOT EXISTS `user_friends` (
+    `user_id` integer,
+    `friend_id` integer,
+    PRIMARY KEY (`user_id`,`friend_id`),
+    CONSTRAINT `fk_user_friends_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_user_friends_friends` FOREIGN KEY (`friend_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `balances` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `user_id` integer NOT NULL,
+    `currency_id` integer NOT NULL,
+    `amount` integer NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_balances_currency` FOREIGN KEY (`currency_id`) REFERENCES `currencies`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_users_balances` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_balances_currency_id` ON `balances`(`currency_id`);
+CREATE INDEX IF NOT EXISTS `idx_balances_user_id` ON `balances`(`user_id`);
+
+CREATE TABLE IF NOT EXISTS `game_states` (
+    `game_id` integer PRIMARY KEY AUTOINCREMENT,
+    `current_user_id` integer NOT NULL,
+    `phase` varchar(255) NOT NULL,
+    `dice` text,
+    `slots` text,
+    `dice_left` integer NOT NULL,
+    `turn_score` integer NOT NULL DEFAULT 0,
+    `turn` integer NOT NULL DEFAULT 1,
+    `nonce` integer NOT NULL DEFAULT 0,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_games_state` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_game_states_current_user_id` ON `game_states`(`current_user_id`);
+
+CREATE TABLE IF NOT EXISTS `game_rolls` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `game_id` integer NOT NULL,
+    `nonce` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `client_seed` varchar(64) NOT NULL,
+    `slots` text,
+    `dice` text,
+    `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_game_rolls_user_id` ON `game_rolls`(`user_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_game_rolls_nonce` ON `game_rolls`(`game_id`,`nonce`);
+
+CREATE TABLE IF NOT EXISTS `ledger_entries` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `transfer_id` varchar(255) NOT NULL,
+    `account` varchar(255) NOT NULL,
+    `user_id` integer NOT NULL,
+    `currency_id` integer NOT NULL,
+    `game_id` integer,
+    `delta` integer NOT NULL,
+    `reason` varchar(255) NOT NULL,
+    `balance_after` integer NOT NULL,
+    `created_at` datetime,
+    CONSTRAINT `fk_ledger_entries_game` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`),
+    CONSTRAINT `fk_ledger_entries_currency` FOREIGN KEY (`currency_id`) REFERENCES `currencies`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_ledger_entries_created_at` ON `ledger_entries`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_ledger_entries_reason` ON `ledger_entries`(`reason`);
+CREATE INDEX IF NOT EXISTS `idx_ledger_entries_game_id` ON `ledger_entries`(`game_id`);
+CREATE INDEX IF NOT EXISTS `idx_ledger_entries_currency_id` ON `ledger_entries`(`currency_id`);
+CREATE INDEX IF NOT EXISTS `idx_ledger_entries_user_id` ON `ledger_entries`(`user_id`);
+CREATE INDEX IF NOT EXISTS `idx_ledger_entries_account` ON `ledger_entries`(`account`);
+CREATE INDEX IF NOT EXISTS `idx_ledger_entries_transfer_id` ON `ledger_entries`(`transfer_id`);
+
+CREATE TABLE IF NOT EXISTS `friend_requests` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `sender_id` integer NOT NULL,
+    `receiver_id` integer NOT NULL,
+    `status` varchar(255) NOT NULL DEFAULT 'pending',
+    `responded_at` datetime,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_friend_requests_sender` FOREIGN KEY (`sender_id`) REFERENCES `users`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_friend_requests_receiver` FOREIGN KEY (`receiver_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_friend_requests_status` ON `friend_requests`(`status`);
+CREATE INDEX IF NOT EXISTS `idx_friend_requests_receiver_id` ON `friend_requests`(`receiver_id`);
+CREATE INDEX IF NOT EXISTS `idx_friend_requests_sender_id` ON `friend_requests`(`sender_id`);
+
+CREATE TABLE IF NOT EXISTS `user_achievements` (
+    `user_id` integer NOT NULL,
+    `slug` varchar(64) NOT NULL,
+    `progress` integer NOT NULL DEFAULT 0,
+    `unlocked_at` datetime,
+    `created_at` datetime,
+    `updated_at` datetime,
+    PRIMARY KEY (`user_id`,`slug`),
+    CONSTRAINT `fk_user_achievements_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `inventory_items` (
+    `user_id` integer NOT NULL,
+    `kind` varchar(16) NOT NULL,
+    `slug` varchar(64) NOT NULL,
+    `quantity` integer NOT NULL DEFAULT 0,
+    `created_at` datetime,
+    `updated_at` datetime,
+    PRIMARY KEY (`user_id`,`kind`,`slug`),
+    CONSTRAINT `fk_inventory_items_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `sessions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `user_id` integer NOT NULL,
+    `family_id` varchar(36) NOT NULL,
+    `refresh_token_hash` varchar(64) NOT NULL,
+    `user_agent` varchar(255),
+    `ip` varchar(64),
+    `expires_at` datetime NOT NULL,
+    `revoked_at` datetime,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_sessions_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_sessions_refresh_token_hash` ON `sessions`(`refresh_token_hash`);
+CREATE INDEX IF NOT EXISTS `idx_sessions_family_id` ON `sessions`(`family_id`);
+CREATE INDEX IF NOT EXISTS `idx_sessions_user_id` ON `sessions`(`user_id`);
+
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `event` varchar(64) NOT NULL,
+    `user_id` integer,
+    `username` varchar(255),
+    `ip` varchar(64),
+    `detail` varchar(255),
+    `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_created_at` ON `audit_logs`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_ip` ON `audit_logs`(`ip`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_user_id` ON `audit_logs`(`user_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_event` ON `audit_logs`(`event`);
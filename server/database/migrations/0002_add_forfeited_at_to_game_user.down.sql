@@ -0,0 +1 @@
+ALTER TABLE `game_user` DROP COLUMN `forfeited_at`;
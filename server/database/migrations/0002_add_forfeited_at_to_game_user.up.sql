@@ -0,0 +1 @@
+ALTER TABLE `game_user` ADD COLUMN `forfeited_at` datetime;
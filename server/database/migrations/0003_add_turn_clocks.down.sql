@@ -0,0 +1,4 @@
+ALTER TABLE `game_states` DROP COLUMN `turn_started_at`;
+ALTER TABLE `game_user` DROP COLUMN `time_used`;
+ALTER TABLE `games` DROP COLUMN `game_seconds`;
+ALTER TABLE `games` DROP COLUMN `turn_seconds`;
@@ -0,0 +1,4 @@
+ALTER TABLE `games` ADD COLUMN `turn_seconds` integer NOT NULL DEFAULT 0;
+ALTER TABLE `games` ADD COLUMN `game_seconds` integer NOT NULL DEFAULT 0;
+ALTER TABLE `game_user` ADD COLUMN `time_used` integer NOT NULL DEFAULT 0;
+ALTER TABLE `game_states` ADD COLUMN `turn_started_at` datetime;
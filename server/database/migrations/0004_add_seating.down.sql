@@ -0,0 +1,4 @@
+ALTER TABLE `game_user` DROP COLUMN `ready_at`;
+ALTER TABLE `game_user` DROP COLUMN `seat`;
+ALTER TABLE `games` DROP COLUMN `seating`;
+ALTER TABLE `games` DROP COLUMN `min_players`;
@@ -0,0 +1,4 @@
+ALTER TABLE `games` ADD COLUMN `min_players` integer NOT NULL DEFAULT 2;
+ALTER TABLE `games` ADD COLUMN `seating` varchar(255) NOT NULL DEFAULT 'random';
+ALTER TABLE `game_user` ADD COLUMN `seat` integer NOT NULL DEFAULT 0;
+ALTER TABLE `game_user` ADD COLUMN `ready_at` datetime;
@@ -0,0 +1,4 @@
+ALTER TABLE `game_states` DROP COLUMN `last_turns`;
+ALTER TABLE `game_states` DROP COLUMN `last_round_by`;
+ALTER TABLE `game_user` DROP COLUMN `farkles`;
+ALTER TABLE `games` DROP COLUMN `rules`;
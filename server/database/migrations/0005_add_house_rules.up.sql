@@ -0,0 +1,5 @@
+ALTER TABLE `games` ADD COLUMN `rules` text;
+UPDATE `games` SET `rules` = '{"opening_score":0,"farkle_penalty":0,"of_a_kind":"double","partial_straights":true,"catch_up":false}';
+ALTER TABLE `game_user` ADD COLUMN `farkles` integer NOT NULL DEFAULT 0;
+ALTER TABLE `game_states` ADD COLUMN `last_round_by` integer NOT NULL DEFAULT 0;
+ALTER TABLE `game_states` ADD COLUMN `last_turns` text;
@@ -0,0 +1 @@
+ALTER TABLE `games` ADD COLUMN `no_spectators` numeric NOT NULL DEFAULT false;
@@ -4,11 +4,14 @@ import (
 	"app/models"
 	"time"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
-func SeedAll() {
-	seedCurrencies()
+// Seed writes the reference data the game needs. It is idempotent, so it
+// can run after every deploy.
+func Seed(db *gorm.DB) error {
+	return seedCurrencies(db)
 }
 
 type CurrencyItem struct {
@@ -16,7 +19,7 @@ type CurrencyItem struct {
 	Name string
 }
 
-func seedCurrencies() {
+func seedCurrencies(db *gorm.DB) error {
 	now := time.Now()
 	items := []models.Currency{
 		{Slug: models.BRONZE, Name: "Bronze", CreatedAt: now, UpdatedAt: now},
@@ -24,8 +27,8 @@ func seedCurrencies() {
 		{Slug: models.GOLD, Name: "Gold", CreatedAt: now, UpdatedAt: now},
 	}
 
-	DB.Clauses(clause.OnConflict{
+	return db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "slug"}},
 		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
-	}).Create(&items)
+	}).Create(&items).Error
 }
@@ -0,0 +1,88 @@
+// Package events is an in-process publish/subscribe bus for game events.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	PlayerJoined    Type = "player_joined"
+	PlayerLeft      Type = "player_left"
+	PlayerForfeited Type = "player_forfeited"
+	PlayerKicked    Type = "player_kicked"
+	PlayerReady     Type = "player_ready"
+	PlayerUnready   Type = "player_unready"
+	SeatsChanged    Type = "seats_changed"
+	GameStarted     Type = "game_started"
+	GameCancelled   Type = "game_cancelled"
+	Rolled          Type = "rolled"
+	Rerolled        Type = "rerolled"
+	SetAside        Type = "set_aside"
+	Banked          Type = "banked"
+	Farkle          Type = "farkle"
+	FarklePenalty   Type = "farkle_penalty"
+	HotDice         Type = "hot_dice"
+	TurnChanged     Type = "turn_changed"
+	TurnTimedOut    Type = "turn_timed_out"
+	LastRound       Type = "last_round"
+	GameOver        Type = "game_over"
+
+	PlayerConnected    Type = "player_connected"
+	PlayerDisconnected Type = "player_disconnected"
+
+	SpectatorJoined  Type = "spectator_joined"
+	SpectatorLeft    Type = "spectator_left"
+	SpectatingOpened Type = "spectating_opened"
+	SpectatingClosed Type = "spectating_closed"
+
+	AchievementUnlocked Type = "achievement_unlocked"
+)
+
+type Event struct {
+	Type     Type      `json:"type"`
+	GameID   uint      `json:"-"`
+	GameCode string    `json:"game_code"`
+	UserID   uint      `json:"user_id,omitempty"`
+	Data     any       `json:"data,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+type Handler func(event Event)
+
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for every published event. Handlers run on
+// the publisher's goroutine, so they must return quickly and leave slow work,
+// like database queries, to a goroutine of their own.
+func (bus *Bus) Subscribe(handler Handler) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	bus.handlers = append(bus.handlers, handler)
+}
+
+func (bus *Bus) Publish(events ...Event) {
+	bus.mu.RLock()
+	handlers := bus.handlers
+	bus.mu.RUnlock()
+
+	for _, event := range events {
+		if event.At.IsZero() {
+			event.At = time.Now()
+		}
+
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package fairness implements commit–reveal dice rolls.
+//
+// When a game starts the server draws a secret seed and publishes only its
+// SHA-256 hash. Every roll is derived from HMAC-SHA256(serverSeed,
+// "clientSeed:nonce:round"): each output byte below the largest multiple of
+// the die's total weight becomes a face (byte % total, walked through the
+// weights), larger bytes are skipped to avoid modulo bias, and the round is
+// increased whenever a digest runs out of bytes. A standard die has six
+// faces of weight 1, so its face is simply byte % 6 + 1. Once the game is
+// finished the seed is revealed and anyone can recompute every roll.
+package fairness
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+)
+
+const seedBytes = 32
+
+// maxWeight is the largest total weight a die may have, one byte's range.
+const maxWeight = 256
+
+var ErrInvalidWeights = errors.New("die weights must add up to between 1 and 256")
+
+// Die is a die's faces and the relative weight of each face. The weights
+// must add up to at least 1 and at most 256, see Validate.
+type Die struct {
+	Faces   [6]int
+	Weights [6]uint
+}
+
+// Standard is an ordinary fair die.
+var Standard = Die{
+	Faces:   [6]int{1, 2, 3, 4, 5, 6},
+	Weights: [6]uint{1, 1, 1, 1, 1, 1},
+}
+
+// Validate reports whether the die can be thrown. A die whose weights add
+// up to nothing, or to more than a byte can pick from, would skip every
+// byte and never land.
+func (die Die) Validate() error {
+	if total := die.total(); total == 0 || total > maxWeight {
+		return ErrInvalidWeights
+	}
+
+	return nil
+}
+
+func (die Die) total() uint {
+	var total uint
+
+	for _, w := range die.Weights {
+		total += w
+	}
+
+	return total
+}
+
+// face maps an output byte to a face, reporting false when the byte has to
+// be skipped.
+func (die Die) face(b byte) (int, bool) {
+	total := die.total()
+
+	if uint(b) >= maxWeight-maxWeight%total {
+		return 0, false
+	}
+
+	v := uint(b) % total
+
+	for i, w := range die.Weights {
+		if v < w {
+			return die.Faces[i], true
+		}
+
+		v -= w
+	}
+
+	return 0, false
+}
+
+// NewServerSeed returns a random hex encoded server seed.
+func NewServerSeed() (string, error) {
+	return randomHex(seedBytes)
+}
+
+// NewClientSeed returns a random hex encoded client seed for players who
+// do not pick their own.
+func NewClientSeed() (string, error) {
+	return randomHex(seedBytes / 2)
+}
+
+// Hash is the commitment published for a server seed.
+func Hash(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Roll deterministically derives n standard dice from the seeds and nonce.
+func Roll(serverSeed, clientSeed string, nonce uint, n int) []int {
+	dice := make([]Die, n)
+
+	for i := range dice {
+		dice[i] = Standard
+	}
+
+	return throw(serverSeed, clientSeed, nonce, dice)
+}
+
+// RollDice deterministically throws the given dice, in order, from the
+// seeds and nonce. It fails when one of the dice cannot be thrown.
+func RollDice(serverSeed, clientSeed string, nonce uint, dice []Die) ([]int, error) {
+	for _, die := range dice {
+		if err := die.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return throw(serverSeed, clientSeed, nonce, dice), nil
+}
+
+// throw rolls dice that are known to be valid.
+func throw(serverSeed, clientSeed string, nonce uint, dice []Die) []int {
+	faces := make([]int, 0, len(dice))
+
+	for round := 0; len(faces) < len(dice); round++ {
+		for _, b := range digest(serverSeed, clientSeed, nonce, round) {
+			face, ok := dice[len(faces)].face(b)
+
+			if !ok {
+				continue
+			}
+
+			faces = append(faces, face)
+
+			if len(faces) == len(dice) {
+				break
+			}
+		}
+	}
+
+	return faces
+}
+
+// Throw is a single roll made by a Roller.
+type Throw struct {
+	Nonce uint
+	Slots []int
+	Dice  []int
+}
+
+// Roller throws dice from a player's loadout, using the next nonce for
+// every throw. Nonce is the last nonce used. The loadout must hold valid
+// dice, like those of the loadout catalogue.
+type Roller struct {
+	ServerSeed string
+	ClientSeed string
+	Nonce      uint
+	Loadout    []Die
+	Throws     []Throw
+}
+
+func (r *Roller) Roll(slots []int) []int {
+	r.Nonce++
+	dice := throw(r.ServerSeed, r.ClientSeed, r.Nonce, LoadoutDice(r.Loadout, slots))
+	r.Throws = append(r.Throws, Throw{Nonce: r.Nonce, Slots: slices.Clone(slots), Dice: dice})
+
+	return dice
+}
+
+// LoadoutDice picks the dice in the given slots of a loadout. Slots outside
+// the loadout, or an empty loadout, use standard dice.
+func LoadoutDice(loadout []Die, slots []int) []Die {
+	dice := make([]Die, len(slots))
+
+	for i, slot := range slots {
+		dice[i] = Standard
+
+		if slot >= 0 && slot < len(loadout) {
+			dice[i] = loadout[slot]
+		}
+	}
+
+	return dice
+}
+
+func digest(serverSeed, clientSeed string, nonce uint, round int) []byte {
+	mac := hmac.New(sha256.New, []byte(serverSeed))
+	mac.Write([]byte(clientSeed + ":" + strconv.FormatUint(uint64(nonce), 10) + ":" + strconv.Itoa(round)))
+
+	return mac.Sum(nil)
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,512 @@
+package farkle
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	ErrNotEnoughPlayers = errors.New("at least two players are required")
+	ErrGameFinished     = errors.New("game is already finished")
+	ErrNotYourTurn      = errors.New("it is not your turn")
+	ErrMustSetAside     = errors.New("set aside scoring dice before rolling again")
+	ErrMustRoll         = errors.New("roll the dice first")
+	ErrDiceNotOnTable   = errors.New("selected dice are not on the table")
+	ErrInvalidSelection = errors.New("selection does not score")
+	ErrNothingToBank    = errors.New("nothing to bank")
+	ErrBadgeUnavailable = errors.New("badge is not available")
+	ErrNotInMatch       = errors.New("player is not in the match")
+	ErrBelowOpening     = errors.New("the first bank must reach the opening score")
+)
+
+type Phase string
+
+const (
+	// PhaseRoll means the current player may roll, or bank a non-zero turn score.
+	PhaseRoll Phase = "roll"
+	// PhaseSetAside means the current player must set aside scoring dice from the last throw.
+	PhaseSetAside Phase = "set_aside"
+	PhaseFinished Phase = "finished"
+)
+
+type Action string
+
+const (
+	ActionRoll     Action = "roll"
+	ActionSetAside Action = "set_aside"
+	ActionBank     Action = "bank"
+	ActionReroll   Action = "reroll"
+	ActionForfeit  Action = "forfeit"
+	ActionTimeOut  Action = "time_out"
+)
+
+// Badge is a once-per-game or passive effect a player brings to a match.
+type Badge string
+
+const (
+	// BadgeScoreBonus adds 10% to every banked turn.
+	BadgeScoreBonus Badge = "score_bonus"
+	// BadgeExtraThrow saves the first farkle of the game with another throw.
+	BadgeExtraThrow Badge = "extra_throw"
+	// BadgeReroll lets the player throw a single die on the table again, once per game.
+	BadgeReroll Badge = "reroll"
+)
+
+// Roller throws the dice in the given loadout slots of the current player.
+type Roller interface {
+	Roll(slots []int) []int
+}
+
+// Match is the state of a game in progress. Players are listed in turn order.
+// Slots holds the loadout slot of each die in Dice; once the dice have been
+// set aside it holds the slots left to roll, and nil means the first DiceLeft.
+// Farkles counts each player's farkles in a row. Under the catch-up rule
+// LastRoundBy is the player who reached the winning points first and
+// LastTurns the players still owed their last turn.
+type Match struct {
+	Players       []uint
+	Totals        map[uint]uint
+	Current       uint
+	Phase         Phase
+	Dice          []int
+	Slots         []int
+	DiceLeft      int
+	TurnScore     uint
+	WinningPoints uint
+	Winner        uint
+	Badges        map[uint]Badge
+	BadgesUsed    map[uint]bool
+	Rules         Rules
+	Farkles       map[uint]int
+	LastRoundBy   uint
+	LastTurns     []uint
+}
+
+// Outcome describes what a single action did to the match.
+type Outcome struct {
+	Action       Action  `json:"action"`
+	PlayerID     uint    `json:"player_id"`
+	Dice         []int   `json:"dice,omitempty"`
+	Result       *Result `json:"result,omitempty"`
+	TurnScore    uint    `json:"turn_score"`
+	Banked       uint    `json:"banked,omitempty"`
+	Bonus        uint    `json:"bonus,omitempty"`
+	ExtraThrow   bool    `json:"extra_throw,omitempty"`
+	Penalty      uint    `json:"penalty,omitempty"`
+	LastRound    bool    `json:"last_round,omitempty"`
+	TimedOut     bool    `json:"timed_out,omitempty"`
+	Farkle       bool    `json:"farkle"`
+	HotDice      bool    `json:"hot_dice"`
+	Finished     bool    `json:"finished"`
+	NextPlayerID uint    `json:"next_player_id"`
+}
+
+func NewMatch(players []uint, winningPoints uint, rules Rules) (*Match, error) {
+	if len(players) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	totals := make(map[uint]uint, len(players))
+
+	for _, p := range players {
+		totals[p] = 0
+	}
+
+	return &Match{
+		Players:       players,
+		Totals:        totals,
+		Current:       players[0],
+		Phase:         PhaseRoll,
+		DiceLeft:      DiceCount,
+		WinningPoints: winningPoints,
+		Badges:        make(map[uint]Badge),
+		BadgesUsed:    make(map[uint]bool),
+		Rules:         rules,
+		Farkles:       make(map[uint]int),
+	}, nil
+}
+
+// Roll throws every die still in play. A throw without any scoring
+// combination is a farkle: the turn score is lost and the turn passes.
+func (m *Match) Roll(player uint, roller Roller) (Outcome, error) {
+	if err := m.checkTurn(player); err != nil {
+		return Outcome{}, err
+	}
+
+	if m.Phase == PhaseSetAside {
+		return Outcome{}, ErrMustSetAside
+	}
+
+	m.Slots = m.inPlay()
+	m.Dice = roller.Roll(m.Slots)
+	outcome := Outcome{Action: ActionRoll, PlayerID: player}
+
+	if !HasAnyScore(m.Dice) && m.useBadge(player, BadgeExtraThrow) {
+		outcome.ExtraThrow = true
+		m.Dice = roller.Roll(m.Slots)
+	}
+
+	outcome.Dice = m.Dice
+	m.settleThrow(&outcome)
+
+	return outcome, nil
+}
+
+// Reroll throws one die on the table again with the reroll badge. The new
+// throw is judged as a whole, so it can still farkle.
+func (m *Match) Reroll(player uint, value int, roller Roller) (Outcome, error) {
+	if err := m.checkTurn(player); err != nil {
+		return Outcome{}, err
+	}
+
+	if m.Phase != PhaseSetAside {
+		return Outcome{}, ErrMustRoll
+	}
+
+	i := slices.Index(m.Dice, value)
+
+	if i < 0 {
+		return Outcome{}, ErrDiceNotOnTable
+	}
+
+	if !m.useBadge(player, BadgeReroll) {
+		return Outcome{}, ErrBadgeUnavailable
+	}
+
+	m.Slots = m.inPlay()
+	m.Dice = slices.Clone(m.Dice)
+	m.Dice[i] = roller.Roll(m.Slots[i : i+1])[0]
+	outcome := Outcome{Action: ActionReroll, PlayerID: player, Dice: m.Dice}
+	m.settleThrow(&outcome)
+
+	return outcome, nil
+}
+
+// SetAside takes scoring dice from the last throw. Setting aside the last
+// die in play is hot dice: all six dice come back for the next roll.
+func (m *Match) SetAside(player uint, values []int) (Outcome, error) {
+	if err := m.checkTurn(player); err != nil {
+		return Outcome{}, err
+	}
+
+	if m.Phase != PhaseSetAside {
+		return Outcome{}, ErrMustRoll
+	}
+
+	rest, restSlots, ok := without(m.Dice, m.inPlay(), values)
+
+	if !ok {
+		return Outcome{}, ErrDiceNotOnTable
+	}
+
+	result := m.Rules.Evaluate(values)
+
+	if !result.Valid {
+		return Outcome{}, ErrInvalidSelection
+	}
+
+	outcome := Outcome{Action: ActionSetAside, PlayerID: player, Dice: values, Result: &result}
+
+	m.TurnScore += result.Score
+	m.DiceLeft -= len(values)
+	m.Dice = rest
+	m.Slots = restSlots
+	m.Phase = PhaseRoll
+
+	if m.DiceLeft == 0 {
+		outcome.HotDice = true
+		m.DiceLeft = DiceCount
+		m.Dice = nil
+		m.Slots = nil
+	}
+
+	outcome.TurnScore = m.TurnScore
+	outcome.NextPlayerID = m.Current
+
+	return outcome, nil
+}
+
+// Bank adds the turn score to the player's total and passes the turn.
+// When the last throw has not been set aside yet, values are set aside first.
+// Reaching the winning points ends the match, or starts the last round
+// under the catch-up rule.
+func (m *Match) Bank(player uint, values []int) (Outcome, error) {
+	if err := m.checkTurn(player); err != nil {
+		return Outcome{}, err
+	}
+
+	outcome := Outcome{Action: ActionBank, PlayerID: player}
+
+	if m.Phase == PhaseSetAside {
+		if len(values) == 0 {
+			return Outcome{}, ErrMustSetAside
+		}
+
+		setAside, err := m.SetAside(player, values)
+
+		if err != nil {
+			return Outcome{}, err
+		}
+
+		outcome.Dice = setAside.Dice
+		outcome.Result = setAside.Result
+		outcome.HotDice = setAside.HotDice
+	}
+
+	if m.TurnScore == 0 {
+		return Outcome{}, ErrNothingToBank
+	}
+
+	if !m.opens(player, m.TurnScore) {
+		return Outcome{}, ErrBelowOpening
+	}
+
+	if m.Badges[player] == BadgeScoreBonus {
+		outcome.Bonus = m.TurnScore / 10
+	}
+
+	outcome.Banked = m.TurnScore + outcome.Bonus
+	m.Totals[player] += outcome.Banked
+	m.TurnScore = 0
+	m.Farkles[player] = 0
+
+	switch {
+	case m.Totals[player] < m.WinningPoints || m.LastRoundBy != 0:
+		m.endTurn(&outcome)
+	case m.Rules.CatchUp:
+		m.LastRoundBy = player
+		m.LastTurns = removePlayer(m.Players, player)
+		outcome.LastRound = true
+		m.nextTurn()
+	default:
+		m.finish(player)
+		outcome.Finished = true
+	}
+
+	outcome.NextPlayerID = m.Current
+
+	return outcome, nil
+}
+
+// Forfeit takes the player out of the match at any point. Their turn, if
+// it was theirs, passes on, and the last player standing wins. In the last
+// round the match ends as soon as nobody is owed a turn any more.
+func (m *Match) Forfeit(player uint) (Outcome, error) {
+	if m.Phase == PhaseFinished {
+		return Outcome{}, ErrGameFinished
+	}
+
+	if !slices.Contains(m.Players, player) {
+		return Outcome{}, ErrNotInMatch
+	}
+
+	if m.Current == player {
+		m.nextTurn()
+	}
+
+	m.Players = removePlayer(m.Players, player)
+	m.LastTurns = removePlayer(m.LastTurns, player)
+	outcome := Outcome{Action: ActionForfeit, PlayerID: player}
+
+	switch {
+	case len(m.Players) == 1:
+		m.finish(m.Players[0])
+		outcome.Finished = true
+	case m.LastRoundBy != 0 && len(m.LastTurns) == 0:
+		m.finish(m.leader())
+		outcome.Finished = true
+	}
+
+	outcome.NextPlayerID = m.Current
+
+	return outcome, nil
+}
+
+// TimeOut ends the turn of a player who ran out of time. Whatever the turn
+// is worth is banked, setting aside the best scoring dice of a throw still
+// on the table, and a turn with nothing to bank passes.
+func (m *Match) TimeOut(player uint) (Outcome, error) {
+	if err := m.checkTurn(player); err != nil {
+		return Outcome{}, err
+	}
+
+	var keep []int
+
+	if m.Phase == PhaseSetAside {
+		keep = m.Rules.BestSelection(m.Dice)
+	}
+
+	if score := m.TurnScore + m.Rules.Evaluate(keep).Score; score > 0 && m.opens(player, score) {
+		outcome, err := m.Bank(player, keep)
+		outcome.TimedOut = err == nil
+
+		return outcome, err
+	}
+
+	outcome := Outcome{Action: ActionTimeOut, PlayerID: player, TimedOut: true}
+	m.endTurn(&outcome)
+	outcome.NextPlayerID = m.Current
+
+	return outcome, nil
+}
+
+// settleThrow busts the turn when the dice on the table do not score.
+func (m *Match) settleThrow(outcome *Outcome) {
+	if !HasAnyScore(m.Dice) {
+		outcome.Farkle = true
+		m.TurnScore = 0
+		m.penalise(outcome)
+		m.endTurn(outcome)
+	} else {
+		m.Phase = PhaseSetAside
+	}
+
+	outcome.TurnScore = m.TurnScore
+	outcome.NextPlayerID = m.Current
+}
+
+// inPlay is the loadout slots of the dice on the table or about to be rolled.
+func (m *Match) inPlay() []int {
+	if len(m.Slots) == m.DiceLeft {
+		return m.Slots
+	}
+
+	slots := make([]int, m.DiceLeft)
+
+	for i := range slots {
+		slots[i] = i
+	}
+
+	return slots
+}
+
+// useBadge spends the player's once-per-game badge, reporting false when
+// the player does not have it or has used it already.
+func (m *Match) useBadge(player uint, badge Badge) bool {
+	if m.Badges[player] != badge || m.BadgesUsed[player] {
+		return false
+	}
+
+	m.BadgesUsed[player] = true
+
+	return true
+}
+
+func (m *Match) checkTurn(player uint) error {
+	if m.Phase == PhaseFinished {
+		return ErrGameFinished
+	}
+
+	if m.Current != player {
+		return ErrNotYourTurn
+	}
+
+	return nil
+}
+
+// opens reports whether banking score is allowed: a player with no points
+// yet needs at least the opening score.
+func (m *Match) opens(player uint, score uint) bool {
+	return m.Totals[player] > 0 || score >= m.Rules.OpeningScore
+}
+
+// penalise counts the current player's farkle and takes the farkle penalty
+// off their total when it completes a run of PenaltyFarkles.
+func (m *Match) penalise(outcome *Outcome) {
+	m.Farkles[m.Current]++
+
+	if m.Farkles[m.Current] < PenaltyFarkles {
+		return
+	}
+
+	m.Farkles[m.Current] = 0
+	outcome.Penalty = min(m.Rules.FarklePenalty, m.Totals[m.Current])
+	m.Totals[m.Current] -= outcome.Penalty
+}
+
+// endTurn passes the turn on, or ends the match when it was the last turn
+// of the last round.
+func (m *Match) endTurn(outcome *Outcome) {
+	if m.LastRoundBy != 0 {
+		m.LastTurns = removePlayer(m.LastTurns, m.Current)
+
+		if len(m.LastTurns) == 0 {
+			m.finish(m.leader())
+			outcome.Finished = true
+
+			return
+		}
+	}
+
+	m.nextTurn()
+}
+
+// leader is the player with the most points. The player who started the
+// last round keeps the lead on a tie.
+func (m *Match) leader() uint {
+	var best uint
+
+	if slices.Contains(m.Players, m.LastRoundBy) {
+		best = m.LastRoundBy
+	}
+
+	for _, p := range m.Players {
+		if best == 0 || m.Totals[p] > m.Totals[best] {
+			best = p
+		}
+	}
+
+	return best
+}
+
+func (m *Match) finish(winner uint) {
+	m.Phase = PhaseFinished
+	m.Winner = winner
+	m.Dice = nil
+	m.Slots = nil
+}
+
+func (m *Match) nextTurn() {
+	next := 0
+
+	for i, p := range m.Players {
+		if p == m.Current {
+			next = (i + 1) % len(m.Players)
+			break
+		}
+	}
+
+	m.Current = m.Players[next]
+	m.Phase = PhaseRoll
+	m.Dice = nil
+	m.Slots = nil
+	m.DiceLeft = DiceCount
+	m.TurnScore = 0
+}
+
+// removePlayer is players without the given one.
+func removePlayer(players []uint, player uint) []uint {
+	return slices.DeleteFunc(slices.Clone(players), func(p uint) bool {
+		return p == player
+	})
+}
+
+// without removes values from dice together with their slots, reporting
+// false when a value is not present. Equal values are taken in slot order.
+func without(dice []int, slots []int, values []int) ([]int, []int, bool) {
+	rest := append([]int(nil), dice...)
+	restSlots := append([]int(nil), slots...)
+
+	for _, v := range values {
+		i := slices.Index(rest, v)
+
+		if i < 0 {
+			return nil, nil, false
+		}
+
+		rest = slices.Delete(rest, i, i+1)
+		restSlots = slices.Delete(restSlots, i, i+1)
+	}
+
+	return rest, restSlots, true
+}
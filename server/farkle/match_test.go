@@ -0,0 +1,393 @@
+package farkle
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// scripted throws the given dice in order, one throw per Roll.
+type scripted struct {
+	throws [][]int
+}
+
+func (roller *scripted) Roll(slots []int) []int {
+	throw := roller.throws[0]
+	roller.throws = roller.throws[1:]
+
+	return slices.Clone(throw[:len(slots)])
+}
+
+func throws(dice ...[]int) *scripted {
+	return &scripted{throws: dice}
+}
+
+var bust = []int{2, 3, 4, 6, 2, 3}
+
+func newMatch(t *testing.T, players []uint, winningPoints uint, rules Rules) *Match {
+	t.Helper()
+
+	m, err := NewMatch(players, winningPoints, rules)
+
+	if err != nil {
+		t.Fatalf("NewMatch: %v", err)
+	}
+
+	return m
+}
+
+// must fails the test on an action's error: must(t)(m.Roll(player, roller)).
+func must(t *testing.T) func(Outcome, error) Outcome {
+	t.Helper()
+
+	return func(outcome Outcome, err error) Outcome {
+		t.Helper()
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return outcome
+	}
+}
+
+func TestNewMatch(t *testing.T) {
+	if _, err := NewMatch([]uint{1}, 1000, StandardRules); !errors.Is(err, ErrNotEnoughPlayers) {
+		t.Fatalf("one player: got %v, want %v", err, ErrNotEnoughPlayers)
+	}
+
+	m := newMatch(t, []uint{1, 2}, 1000, StandardRules)
+
+	if m.Current != 1 || m.Phase != PhaseRoll || m.DiceLeft != DiceCount {
+		t.Fatalf("new match = %+v", m)
+	}
+}
+
+func TestRollSetAsideBank(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+	roller := throws([]int{1, 2, 3, 4, 6, 6}, []int{5, 2, 3, 4, 6})
+
+	if _, err := m.Bank(1, nil); !errors.Is(err, ErrNothingToBank) {
+		t.Fatalf("bank before rolling: got %v, want %v", err, ErrNothingToBank)
+	}
+
+	if _, err := m.SetAside(1, []int{1}); !errors.Is(err, ErrMustRoll) {
+		t.Fatalf("set aside before rolling: got %v, want %v", err, ErrMustRoll)
+	}
+
+	if _, err := m.Roll(2, roller); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("roll out of turn: got %v, want %v", err, ErrNotYourTurn)
+	}
+
+	outcome := must(t)(m.Roll(1, roller))
+
+	if outcome.Farkle || m.Phase != PhaseSetAside || !slices.Equal(m.Dice, []int{1, 2, 3, 4, 6, 6}) {
+		t.Fatalf("roll = %+v, phase %s", outcome, m.Phase)
+	}
+
+	errorTests := []struct {
+		name   string
+		action func() error
+		want   error
+	}{
+		{"roll again", func() error { _, err := m.Roll(1, roller); return err }, ErrMustSetAside},
+		{"bank without dice", func() error { _, err := m.Bank(1, nil); return err }, ErrMustSetAside},
+		{"dice not thrown", func() error { _, err := m.SetAside(1, []int{5}); return err }, ErrDiceNotOnTable},
+		{"dice thrown once", func() error { _, err := m.SetAside(1, []int{1, 1}); return err }, ErrDiceNotOnTable},
+		{"dice that do not score", func() error { _, err := m.SetAside(1, []int{1, 2}); return err }, ErrInvalidSelection},
+		{"out of turn", func() error { _, err := m.SetAside(2, []int{1}); return err }, ErrNotYourTurn},
+	}
+
+	for _, tt := range errorTests {
+		if err := tt.action(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	outcome = must(t)(m.SetAside(1, []int{1}))
+
+	if outcome.TurnScore != 100 || m.DiceLeft != 5 || m.Phase != PhaseRoll || !slices.Equal(m.Dice, []int{2, 3, 4, 6, 6}) {
+		t.Fatalf("set aside = %+v, dice left %d", outcome, m.DiceLeft)
+	}
+
+	must(t)(m.Roll(1, roller))
+	outcome = must(t)(m.Bank(1, []int{5}))
+
+	if outcome.Banked != 150 || m.Totals[1] != 150 || m.Current != 2 || outcome.NextPlayerID != 2 {
+		t.Fatalf("bank = %+v, totals %v", outcome, m.Totals)
+	}
+
+	if m.Phase != PhaseRoll || m.TurnScore != 0 || m.DiceLeft != DiceCount || m.Dice != nil {
+		t.Fatalf("next turn starts dirty: %+v", m)
+	}
+}
+
+func TestHotDice(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+	must(t)(m.Roll(1, throws([]int{1, 5, 1, 5, 1, 1})))
+	outcome := must(t)(m.SetAside(1, []int{1, 1, 1, 1, 5, 5}))
+
+	if !outcome.HotDice || outcome.TurnScore != 2100 || m.DiceLeft != DiceCount || m.Dice != nil {
+		t.Fatalf("hot dice = %+v, dice left %d", outcome, m.DiceLeft)
+	}
+
+	outcome = must(t)(m.Roll(1, throws([]int{5, 2, 3, 4, 6, 6})))
+
+	if len(outcome.Dice) != DiceCount || m.Current != 1 {
+		t.Fatalf("roll after hot dice = %+v", outcome)
+	}
+}
+
+func TestFarkle(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+	must(t)(m.Roll(1, throws([]int{1, 2, 3, 4, 6, 6})))
+	must(t)(m.SetAside(1, []int{1}))
+	outcome := must(t)(m.Roll(1, throws(bust)))
+
+	if !outcome.Farkle || outcome.TurnScore != 0 || m.Totals[1] != 0 || m.Current != 2 || m.Farkles[1] != 1 {
+		t.Fatalf("farkle = %+v, totals %v", outcome, m.Totals)
+	}
+}
+
+func TestWinning(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 1000, StandardRules)
+	must(t)(m.Roll(1, throws([]int{1, 1, 1, 2, 3, 4})))
+	outcome := must(t)(m.Bank(1, []int{1, 1, 1}))
+
+	if !outcome.Finished || m.Phase != PhaseFinished || m.Winner != 1 {
+		t.Fatalf("winning bank = %+v, winner %d", outcome, m.Winner)
+	}
+
+	if _, err := m.Roll(1, throws(bust)); !errors.Is(err, ErrGameFinished) {
+		t.Fatalf("roll after the end: got %v, want %v", err, ErrGameFinished)
+	}
+}
+
+func TestOpeningScore(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 5000, Rules{OfAKind: OfAKindDouble, OpeningScore: 500})
+	must(t)(m.Roll(1, throws([]int{1, 2, 3, 4, 6, 6})))
+	must(t)(m.SetAside(1, []int{1}))
+
+	if _, err := m.Bank(1, nil); !errors.Is(err, ErrBelowOpening) {
+		t.Fatalf("bank below the opening score: got %v, want %v", err, ErrBelowOpening)
+	}
+
+	must(t)(m.Roll(1, throws([]int{5, 5, 5, 2, 3})))
+	outcome := must(t)(m.Bank(1, []int{5, 5, 5}))
+
+	if outcome.Banked != 600 || m.Totals[1] != 600 {
+		t.Fatalf("opening bank = %+v", outcome)
+	}
+
+	// once on the board any score may be banked
+	m.Current = 1
+	must(t)(m.Roll(1, throws([]int{5, 2, 3, 4, 6, 6})))
+	outcome = must(t)(m.Bank(1, []int{5}))
+
+	if outcome.Banked != 50 || m.Totals[1] != 650 {
+		t.Fatalf("bank after opening = %+v", outcome)
+	}
+}
+
+func TestFarklePenalty(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 5000, Rules{OfAKind: OfAKindDouble, FarklePenalty: 500})
+	m.Totals[1] = 300
+
+	for i := 1; i < PenaltyFarkles; i++ {
+		outcome := must(t)(m.Roll(1, throws(bust)))
+		must(t)(m.Roll(2, throws(bust)))
+
+		if outcome.Penalty != 0 || m.Farkles[1] != i {
+			t.Fatalf("farkle %d = %+v, farkles %d", i, outcome, m.Farkles[1])
+		}
+	}
+
+	outcome := must(t)(m.Roll(1, throws(bust)))
+
+	if outcome.Penalty != 300 || m.Totals[1] != 0 || m.Farkles[1] != 0 {
+		t.Fatalf("penalty = %+v, totals %v", outcome, m.Totals)
+	}
+}
+
+func TestFarklePenaltyResetsOnBank(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 5000, Rules{OfAKind: OfAKindDouble, FarklePenalty: 500})
+	m.Totals[1] = 1000
+	m.Farkles[1] = PenaltyFarkles - 1
+
+	must(t)(m.Roll(1, throws([]int{5, 2, 3, 4, 6, 6})))
+	must(t)(m.Bank(1, []int{5}))
+
+	if m.Farkles[1] != 0 {
+		t.Fatalf("farkles after a bank = %d", m.Farkles[1])
+	}
+}
+
+func TestFarklePenaltyOff(t *testing.T) {
+	m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+	m.Totals[1] = 300
+	m.Farkles[1] = PenaltyFarkles - 1
+	outcome := must(t)(m.Roll(1, throws(bust)))
+
+	if outcome.Penalty != 0 || m.Totals[1] != 300 {
+		t.Fatalf("penalty without the rule = %+v", outcome)
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	catchUp := Rules{OfAKind: OfAKindDouble, CatchUp: true}
+
+	t.Run("the leader holds on", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2, 3}, 1000, catchUp)
+		must(t)(m.Roll(1, throws([]int{1, 1, 1, 2, 3, 4})))
+		outcome := must(t)(m.Bank(1, []int{1, 1, 1}))
+
+		if !outcome.LastRound || outcome.Finished || m.LastRoundBy != 1 || !slices.Equal(m.LastTurns, []uint{2, 3}) {
+			t.Fatalf("last round = %+v, last turns %v", outcome, m.LastTurns)
+		}
+
+		must(t)(m.Roll(2, throws(bust)))
+		outcome = must(t)(m.Roll(3, throws(bust)))
+
+		if !outcome.Finished || m.Winner != 1 {
+			t.Fatalf("end of last round = %+v, winner %d", outcome, m.Winner)
+		}
+	})
+
+	t.Run("a later player overtakes", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 1000, catchUp)
+		must(t)(m.Roll(1, throws([]int{1, 1, 1, 2, 3, 4})))
+		must(t)(m.Bank(1, []int{1, 1, 1}))
+		must(t)(m.Roll(2, throws([]int{1, 1, 1, 1, 3, 4})))
+		outcome := must(t)(m.Bank(2, []int{1, 1, 1, 1}))
+
+		if !outcome.Finished || m.Winner != 2 {
+			t.Fatalf("overtaking bank = %+v, winner %d", outcome, m.Winner)
+		}
+	})
+
+	t.Run("a tie goes to the player who reached it first", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 1000, catchUp)
+		must(t)(m.Roll(1, throws([]int{1, 1, 1, 2, 3, 4})))
+		must(t)(m.Bank(1, []int{1, 1, 1}))
+		must(t)(m.Roll(2, throws([]int{1, 1, 1, 2, 3, 4})))
+		must(t)(m.Bank(2, []int{1, 1, 1}))
+
+		if m.Phase != PhaseFinished || m.Winner != 1 {
+			t.Fatalf("tie winner = %d", m.Winner)
+		}
+	})
+}
+
+func TestForfeit(t *testing.T) {
+	m := newMatch(t, []uint{1, 2, 3}, 5000, StandardRules)
+	must(t)(m.Roll(1, throws([]int{1, 2, 3, 4, 6, 6})))
+
+	if _, err := m.Forfeit(4); !errors.Is(err, ErrNotInMatch) {
+		t.Fatalf("forfeit by a stranger: got %v, want %v", err, ErrNotInMatch)
+	}
+
+	outcome := must(t)(m.Forfeit(1))
+
+	if outcome.Finished || m.Current != 2 || m.Phase != PhaseRoll || !slices.Equal(m.Players, []uint{2, 3}) {
+		t.Fatalf("forfeit on turn = %+v, players %v", outcome, m.Players)
+	}
+
+	outcome = must(t)(m.Forfeit(3))
+
+	if !outcome.Finished || m.Winner != 2 {
+		t.Fatalf("last player standing = %+v, winner %d", outcome, m.Winner)
+	}
+
+	if _, err := m.Forfeit(2); !errors.Is(err, ErrGameFinished) {
+		t.Fatalf("forfeit after the end: got %v, want %v", err, ErrGameFinished)
+	}
+}
+
+func TestForfeitInLastRound(t *testing.T) {
+	m := newMatch(t, []uint{1, 2, 3}, 1000, Rules{OfAKind: OfAKindDouble, CatchUp: true})
+	must(t)(m.Roll(1, throws([]int{1, 1, 1, 2, 3, 4})))
+	must(t)(m.Bank(1, []int{1, 1, 1}))
+	must(t)(m.Forfeit(3))
+	outcome := must(t)(m.Forfeit(2))
+
+	if !outcome.Finished || m.Winner != 1 {
+		t.Fatalf("nobody owed a turn = %+v, winner %d", outcome, m.Winner)
+	}
+}
+
+func TestTimeOut(t *testing.T) {
+	t.Run("banks the best dice on the table", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+		must(t)(m.Roll(1, throws([]int{1, 5, 2, 2, 3, 6})))
+		outcome := must(t)(m.TimeOut(1))
+
+		if !outcome.TimedOut || outcome.Action != ActionBank || outcome.Banked != 150 || m.Current != 2 {
+			t.Fatalf("time out = %+v", outcome)
+		}
+	})
+
+	t.Run("passes a turn with nothing to bank", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+		outcome := must(t)(m.TimeOut(1))
+
+		if !outcome.TimedOut || outcome.Action != ActionTimeOut || outcome.Banked != 0 || m.Current != 2 {
+			t.Fatalf("time out = %+v", outcome)
+		}
+	})
+
+	t.Run("passes a turn below the opening score", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 5000, Rules{OfAKind: OfAKindDouble, OpeningScore: 500})
+		must(t)(m.Roll(1, throws([]int{1, 2, 3, 4, 6, 6})))
+		outcome := must(t)(m.TimeOut(1))
+
+		if outcome.Action != ActionTimeOut || m.Totals[1] != 0 || m.Current != 2 {
+			t.Fatalf("time out = %+v", outcome)
+		}
+	})
+
+	t.Run("out of turn", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+
+		if _, err := m.TimeOut(2); !errors.Is(err, ErrNotYourTurn) {
+			t.Fatalf("got %v, want %v", err, ErrNotYourTurn)
+		}
+	})
+}
+
+func TestBadges(t *testing.T) {
+	t.Run("score bonus", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+		m.Badges[1] = BadgeScoreBonus
+		must(t)(m.Roll(1, throws([]int{1, 1, 1, 2, 3, 4})))
+		outcome := must(t)(m.Bank(1, []int{1, 1, 1}))
+
+		if outcome.Bonus != 100 || outcome.Banked != 1100 {
+			t.Fatalf("bonus bank = %+v", outcome)
+		}
+	})
+
+	t.Run("extra throw saves the first farkle", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+		m.Badges[1] = BadgeExtraThrow
+		outcome := must(t)(m.Roll(1, throws(bust, []int{1, 2, 3, 4, 6, 6})))
+
+		if !outcome.ExtraThrow || outcome.Farkle || m.Phase != PhaseSetAside || !m.BadgesUsed[1] {
+			t.Fatalf("extra throw = %+v", outcome)
+		}
+	})
+
+	t.Run("reroll once per game", func(t *testing.T) {
+		m := newMatch(t, []uint{1, 2}, 5000, StandardRules)
+		m.Badges[1] = BadgeReroll
+		must(t)(m.Roll(1, throws([]int{1, 2, 3, 4, 6, 6})))
+		outcome := must(t)(m.Reroll(1, 2, throws([]int{5})))
+
+		if !slices.Equal(outcome.Dice, []int{1, 5, 3, 4, 6, 6}) {
+			t.Fatalf("reroll = %+v", outcome)
+		}
+
+		if _, err := m.Reroll(1, 3, throws([]int{5})); !errors.Is(err, ErrBadgeUnavailable) {
+			t.Fatalf("second reroll: got %v, want %v", err, ErrBadgeUnavailable)
+		}
+	})
+}
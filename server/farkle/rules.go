@@ -0,0 +1,33 @@
+package farkle
+
+const (
+	// OfAKindDouble doubles the score of three of a kind for every extra die.
+	OfAKindDouble = "double"
+	// OfAKindAdd adds the score of three of a kind again for every extra die.
+	OfAKindAdd = "add"
+)
+
+// PenaltyFarkles is how many farkles in a row cost a player the farkle penalty.
+const PenaltyFarkles = 3
+
+// Rules are the house rules a match is played by.
+type Rules struct {
+	// OpeningScore is the least a player with no points must bank to get on the board.
+	OpeningScore uint `json:"opening_score"`
+	// FarklePenalty is taken off the total of a player who farkles
+	// PenaltyFarkles turns in a row; zero turns the penalty off.
+	FarklePenalty uint `json:"farkle_penalty"`
+	// OfAKind is how four, five and six of a kind score.
+	OfAKind string `json:"of_a_kind"`
+	// PartialStraights lets 1–5 and 2–6 score as well as the full straight.
+	PartialStraights bool `json:"partial_straights"`
+	// CatchUp gives every other player one last turn once someone reaches
+	// the winning points; the highest total then wins.
+	CatchUp bool `json:"catch_up"`
+}
+
+// StandardRules are the Kingdom Come rules.
+var StandardRules = Rules{
+	OfAKind:          OfAKindDouble,
+	PartialStraights: true,
+}
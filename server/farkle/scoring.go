@@ -0,0 +1,230 @@
+package farkle
+
+import (
+	"slices"
+	"sort"
+)
+
+const (
+	MinFace   = 1
+	MaxFace   = 6
+	DiceCount = 6
+)
+
+// Wildcard is the devil's head face of special dice. It stands in for
+// whichever face scores the selection best.
+const Wildcard = 0
+
+const (
+	LabelSelectDice       = "Select dice"
+	LabelInvalidSelection = "Invalid selection"
+	LabelScoringDice      = "Scoring dice"
+	LabelStraightFull     = "Straight 1–6"
+	LabelStraightLow      = "Straight 1–5"
+	LabelStraightHigh     = "Straight 2–6"
+)
+
+// Result is the outcome of evaluating a selection of dice.
+type Result struct {
+	Valid bool   `json:"valid"`
+	Score uint   `json:"score"`
+	Label string `json:"label"`
+}
+
+type straight struct {
+	faces []int
+	score uint
+	label string
+}
+
+// straights are checked in order, the full straight first.
+var straights = []straight{
+	{faces: []int{1, 2, 3, 4, 5, 6}, score: 1500, label: LabelStraightFull},
+	{faces: []int{1, 2, 3, 4, 5}, score: 500, label: LabelStraightLow},
+	{faces: []int{2, 3, 4, 5, 6}, score: 750, label: LabelStraightHigh},
+}
+
+// Evaluate scores a selection of dice using the Kingdom Come rules.
+// Every die in the selection must contribute to the score, otherwise
+// the selection is invalid.
+func Evaluate(values []int) Result {
+	return StandardRules.Evaluate(values)
+}
+
+// Evaluate scores a selection of dice under the house rules.
+func (rules Rules) Evaluate(values []int) Result {
+	if len(values) == 0 {
+		return Result{Valid: false, Score: 0, Label: LabelSelectDice}
+	}
+
+	if !validFaces(values) {
+		return invalid()
+	}
+
+	if i := slices.Index(values, Wildcard); i >= 0 {
+		return rules.evaluateWildcard(values, i)
+	}
+
+	for _, s := range straights {
+		if len(s.faces) < DiceCount && !rules.PartialStraights {
+			continue
+		}
+
+		if isStraight(values, s.faces) {
+			return Result{Valid: true, Score: s.score, Label: s.label}
+		}
+	}
+
+	counts := countFaces(values)
+	var score uint
+
+	for face := MinFace; face <= MaxFace; face++ {
+		n := counts[face]
+
+		if n == 0 {
+			continue
+		}
+
+		if n >= 3 {
+			score += rules.ofAKind(face, n)
+			continue
+		}
+
+		switch face {
+		case 1:
+			score += uint(n) * 100
+		case 5:
+			score += uint(n) * 50
+		default:
+			return invalid()
+		}
+	}
+
+	if score == 0 {
+		return invalid()
+	}
+
+	return Result{Valid: true, Score: score, Label: LabelScoringDice}
+}
+
+// HasAnyScore reports whether a throw contains at least one scoring
+// combination. A throw without one is a farkle.
+func HasAnyScore(values []int) bool {
+	if len(values) == 0 || !validFaces(values) {
+		return false
+	}
+
+	counts := countFaces(values)
+
+	if counts[Wildcard] > 0 || counts[1] > 0 || counts[5] > 0 {
+		return true
+	}
+
+	// every straight contains a 5, so only triples are left to check
+	for face := MinFace; face <= MaxFace; face++ {
+		if counts[face] >= 3 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BestSelection is the scoring selection from a throw worth the most
+// points, or nil when the throw does not score.
+func (rules Rules) BestSelection(dice []int) []int {
+	var best []int
+	var bestScore uint
+
+	for mask := 1; mask < 1<<len(dice); mask++ {
+		var keep []int
+
+		for i, d := range dice {
+			if mask&(1<<i) != 0 {
+				keep = append(keep, d)
+			}
+		}
+
+		if result := rules.Evaluate(keep); result.Valid && result.Score > bestScore {
+			best, bestScore = keep, result.Score
+		}
+	}
+
+	return best
+}
+
+// evaluateWildcard tries every face in place of the wildcard at index i
+// and keeps the best scoring result.
+func (rules Rules) evaluateWildcard(values []int, i int) Result {
+	substituted := slices.Clone(values)
+	best := invalid()
+
+	for face := MinFace; face <= MaxFace; face++ {
+		substituted[i] = face
+		result := rules.Evaluate(substituted)
+
+		if result.Valid && result.Score > best.Score {
+			best = result
+		}
+	}
+
+	return best
+}
+
+// ofAKind scores three or more dice of the same face. Three 1s are worth
+// 1000 and any other triple is worth face*100. Each extra die doubles it,
+// or adds the triple again when the house adds.
+func (rules Rules) ofAKind(face, n int) uint {
+	base := uint(face) * 100
+
+	if face == 1 {
+		base = 1000
+	}
+
+	if rules.OfAKind == OfAKindAdd {
+		return base * uint(n-2)
+	}
+
+	return base << uint(n-3)
+}
+
+func isStraight(values []int, target []int) bool {
+	if len(values) != len(target) {
+		return false
+	}
+
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	for i := range target {
+		if sorted[i] != target[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func countFaces(values []int) [MaxFace + 1]int {
+	var counts [MaxFace + 1]int
+
+	for _, v := range values {
+		counts[v]++
+	}
+
+	return counts
+}
+
+func validFaces(values []int) bool {
+	for _, v := range values {
+		if v != Wildcard && (v < MinFace || v > MaxFace) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func invalid() Result {
+	return Result{Valid: false, Score: 0, Label: LabelInvalidSelection}
+}
@@ -0,0 +1,156 @@
+package farkle
+
+import (
+	"slices"
+	"testing"
+)
+
+var addRules = Rules{OfAKind: OfAKindAdd, PartialStraights: true}
+
+var fullStraightOnly = Rules{OfAKind: OfAKindDouble}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  Rules
+		values []int
+		want   Result
+	}{
+		{"empty selection", StandardRules, nil, Result{Label: LabelSelectDice}},
+		{"empty slice", StandardRules, []int{}, Result{Label: LabelSelectDice}},
+
+		{"single one", StandardRules, []int{1}, scoring(100)},
+		{"single five", StandardRules, []int{5}, scoring(50)},
+		{"two ones", StandardRules, []int{1, 1}, scoring(200)},
+		{"two fives", StandardRules, []int{5, 5}, scoring(100)},
+		{"one and five", StandardRules, []int{5, 1}, scoring(150)},
+
+		{"three ones", StandardRules, []int{1, 1, 1}, scoring(1000)},
+		{"three twos", StandardRules, []int{2, 2, 2}, scoring(200)},
+		{"three threes", StandardRules, []int{3, 3, 3}, scoring(300)},
+		{"three fours", StandardRules, []int{4, 4, 4}, scoring(400)},
+		{"three fives", StandardRules, []int{5, 5, 5}, scoring(500)},
+		{"three sixes", StandardRules, []int{6, 6, 6}, scoring(600)},
+		{"triple with singles", StandardRules, []int{2, 2, 1, 2, 5}, scoring(350)},
+		{"two triples", StandardRules, []int{3, 3, 3, 4, 4, 4}, scoring(700)},
+
+		{"four twos doubled", StandardRules, []int{2, 2, 2, 2}, scoring(400)},
+		{"five twos doubled", StandardRules, []int{2, 2, 2, 2, 2}, scoring(800)},
+		{"six twos doubled", StandardRules, []int{2, 2, 2, 2, 2, 2}, scoring(1600)},
+		{"four ones doubled", StandardRules, []int{1, 1, 1, 1}, scoring(2000)},
+		{"five ones doubled", StandardRules, []int{1, 1, 1, 1, 1}, scoring(4000)},
+		{"six ones doubled", StandardRules, []int{1, 1, 1, 1, 1, 1}, scoring(8000)},
+		{"four twos added", addRules, []int{2, 2, 2, 2}, scoring(400)},
+		{"five twos added", addRules, []int{2, 2, 2, 2, 2}, scoring(600)},
+		{"six twos added", addRules, []int{2, 2, 2, 2, 2, 2}, scoring(800)},
+		{"four ones added", addRules, []int{1, 1, 1, 1}, scoring(2000)},
+		{"five ones added", addRules, []int{1, 1, 1, 1, 1}, scoring(3000)},
+		{"six ones added", addRules, []int{1, 1, 1, 1, 1, 1}, scoring(4000)},
+		{"triple added as usual", addRules, []int{6, 6, 6}, scoring(600)},
+
+		{"full straight", StandardRules, []int{4, 2, 6, 1, 3, 5}, straightOf(1500, LabelStraightFull)},
+		{"low straight", StandardRules, []int{5, 4, 3, 2, 1}, straightOf(500, LabelStraightLow)},
+		{"high straight", StandardRules, []int{2, 3, 4, 5, 6}, straightOf(750, LabelStraightHigh)},
+		{"full straight without partials", fullStraightOnly, []int{1, 2, 3, 4, 5, 6}, straightOf(1500, LabelStraightFull)},
+		{"low straight without partials", fullStraightOnly, []int{1, 2, 3, 4, 5}, invalid()},
+		{"high straight without partials", fullStraightOnly, []int{2, 3, 4, 5, 6}, invalid()},
+		{"broken straight", StandardRules, []int{1, 2, 3, 4, 6}, invalid()},
+
+		{"wildcard alone", StandardRules, []int{Wildcard}, scoring(100)},
+		{"wildcard completes a triple", StandardRules, []int{2, Wildcard, 2}, scoring(200)},
+		{"wildcard completes triple ones", StandardRules, []int{1, 1, Wildcard}, scoring(1000)},
+		{"wildcard extends of a kind", StandardRules, []int{1, 1, 1, Wildcard}, scoring(2000)},
+		{"wildcard extends of a kind added", addRules, []int{1, 1, 1, Wildcard}, scoring(2000)},
+		{"wildcard completes high straight", StandardRules, []int{Wildcard, 2, 3, 4, 5}, straightOf(750, LabelStraightHigh)},
+		{"wildcard completes full straight", StandardRules, []int{2, 3, Wildcard, 4, 5, 6}, straightOf(1500, LabelStraightFull)},
+		{"wildcard without partial straights", fullStraightOnly, []int{Wildcard, 2, 3, 4, 5}, invalid()},
+		{"two wildcards", StandardRules, []int{Wildcard, Wildcard}, scoring(200)},
+		{"wildcard cannot save a dead die", StandardRules, []int{Wildcard, 3}, invalid()},
+
+		{"face too high", StandardRules, []int{7}, invalid()},
+		{"negative face", StandardRules, []int{-1}, invalid()},
+		{"invalid face with scoring dice", StandardRules, []int{1, 1, 1, 9}, invalid()},
+
+		{"lone two", StandardRules, []int{2}, invalid()},
+		{"pair of twos", StandardRules, []int{2, 2}, invalid()},
+		{"one with a dead die", StandardRules, []int{1, 2}, invalid()},
+		{"singles with a dead die", StandardRules, []int{1, 5, 3}, invalid()},
+		{"triple with a dead die", StandardRules, []int{2, 2, 2, 3}, invalid()},
+		{"pairs are not scored", StandardRules, []int{2, 2, 3, 3, 4, 4}, invalid()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Evaluate(tt.values); got != tt.want {
+				t.Errorf("Evaluate(%v) = %+v, want %+v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateUsesStandardRules(t *testing.T) {
+	for _, values := range [][]int{{2, 2, 2, 2, 2}, {1, 2, 3, 4, 5}, {Wildcard, 5}} {
+		if got, want := Evaluate(values), StandardRules.Evaluate(values); got != want {
+			t.Errorf("Evaluate(%v) = %+v, want %+v", values, got, want)
+		}
+	}
+}
+
+func TestHasAnyScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   bool
+	}{
+		{"empty throw", nil, false},
+		{"farkle", []int{2, 3, 4, 6, 2, 3}, false},
+		{"pairs only", []int{2, 2, 3, 3, 4, 4}, false},
+		{"a one", []int{2, 3, 1, 6}, true},
+		{"a five", []int{5, 2}, true},
+		{"a triple", []int{4, 2, 4, 3, 4, 6}, true},
+		{"a wildcard", []int{Wildcard, 2, 3}, true},
+		{"invalid face", []int{7, 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasAnyScore(tt.values); got != tt.want {
+				t.Errorf("HasAnyScore(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestSelection(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		dice  []int
+		want  []int
+	}{
+		{"farkle", StandardRules, []int{2, 3, 4, 6, 2, 3}, nil},
+		{"full straight", StandardRules, []int{6, 5, 4, 3, 2, 1}, []int{6, 5, 4, 3, 2, 1}},
+		{"triple and a five", StandardRules, []int{1, 2, 1, 5, 1, 3}, []int{1, 1, 5, 1}},
+		{"high straight", StandardRules, []int{2, 3, 4, 5, 6, 6}, []int{2, 3, 4, 5, 6}},
+		{"high straight off", fullStraightOnly, []int{2, 3, 4, 5, 6, 6}, []int{5}},
+		{"five of a kind doubled", StandardRules, []int{3, 3, 3, 3, 3, 1}, []int{3, 3, 3, 3, 3, 1}},
+		{"five of a kind added", addRules, []int{3, 3, 3, 3, 3, 1}, []int{3, 3, 3, 3, 3, 1}},
+		{"wildcard", StandardRules, []int{Wildcard, 2, 2}, []int{Wildcard, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.BestSelection(tt.dice); !slices.Equal(got, tt.want) {
+				t.Errorf("BestSelection(%v) = %v, want %v", tt.dice, got, tt.want)
+			}
+		})
+	}
+}
+
+func scoring(score uint) Result {
+	return Result{Valid: true, Score: score, Label: LabelScoringDice}
+}
+
+func straightOf(score uint, label string) Result {
+	return Result{Valid: true, Score: score, Label: label}
+}
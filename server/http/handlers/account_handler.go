@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/services"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+func (handler *AccountHandler) ChangePassword(c fiber.Ctx) error {
+	input := new(inputs.ChangePasswordInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	sessionId, err := GetSessionID(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.accountService.ChangePassword(authUser, sessionId, *input); err != nil {
+		return accountError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Password changed",
+	})
+}
+
+func (handler *AccountHandler) Rename(c fiber.Ctx) error {
+	input := new(inputs.RenameInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	user, err := handler.accountService.Rename(authUser, *input)
+
+	if err != nil {
+		return accountError(c, err)
+	}
+
+	return c.JSON(responses.UserResponse{
+		Data: responses.NewUserResource(*user),
+	})
+}
+
+func (handler *AccountHandler) DeleteAccount(c fiber.Ctx) error {
+	input := new(inputs.DeleteAccountInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.accountService.Delete(authUser, *input); err != nil {
+		return accountError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Account deleted",
+	})
+}
+
+func accountError(c fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	message := "Internal Server Error"
+
+	switch {
+	case errors.Is(err, services.ErrWrongPassword):
+		status, message = fiber.StatusBadRequest, err.Error()
+	case errors.Is(err, services.ErrUsernameTaken),
+		errors.Is(err, services.ErrUsernameReserved):
+		status, message = fiber.StatusConflict, err.Error()
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"message": message,
+	})
+}
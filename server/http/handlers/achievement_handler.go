@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"app/http/responses"
+	"app/services"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type AchievementHandler struct {
+	achievementService *services.AchievementService
+}
+
+func NewAchievementHandler(achievementService *services.AchievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService: achievementService}
+}
+
+func (handler *AchievementHandler) GetAchievements(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	statuses, err := handler.achievementService.List(authUser)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(responses.NewAchievementsResponse(statuses))
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
-	"app/config"
-	"app/models"
+	"app/services"
 	"app/utils"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	jwtware "github.com/gofiber/contrib/v3/jwt"
 	"github.com/gofiber/fiber/v3"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -16,7 +20,29 @@ type LoginInput struct {
 	Password string `json:"password" validate:"required,min=6,max=50"`
 }
 
-func Login(c fiber.Ctx) error {
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type AuthHandler struct {
+	sessionService *services.SessionService
+	accountService *services.AccountService
+	loginGuard     *services.LoginGuard
+}
+
+func NewAuthHandler(
+	sessionService *services.SessionService,
+	accountService *services.AccountService,
+	loginGuard *services.LoginGuard,
+) *AuthHandler {
+	return &AuthHandler{
+		sessionService: sessionService,
+		accountService: accountService,
+		loginGuard:     loginGuard,
+	}
+}
+
+func (handler *AuthHandler) Login(c fiber.Ctx) error {
 	userData, err := validateUserData(c)
 
 	if err != nil {
@@ -25,7 +51,13 @@ func Login(c fiber.Ctx) error {
 		})
 	}
 
-	user, err := getUserByUsername(userData.Username)
+	user, err := handler.accountService.FindByUsername(userData.Username)
+
+	// the attempt counts before the password is checked, so a burst of
+	// guesses is throttled as it arrives
+	if wait := handler.loginGuard.AttemptLogin(userData.Username, c.IP(), user); wait > 0 {
+		return tooManyAttempts(c, wait)
+	}
 
 	const dummyHash = "2a$10$7zFqzDbD3RrlkMTczbXG9OWZ0FLOXjIxXzSZ.QZxkVXjXcx7QZQiC"
 
@@ -44,7 +76,9 @@ func Login(c fiber.Ctx) error {
 		})
 	}
 
-	token, err := createToken(*user)
+	handler.loginGuard.LoginSucceeded(user, c.IP())
+
+	tokens, err := handler.sessionService.SignIn(*user, sessionClient(c))
 
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -52,13 +86,10 @@ func Login(c fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Logged in",
-		"token":   token,
-	})
+	return tokensResponse(c, "Logged in", tokens)
 }
 
-func Register(c fiber.Ctx) error {
+func (handler *AuthHandler) Register(c fiber.Ctx) error {
 	userData, err := validateUserData(c)
 
 	if err != nil {
@@ -67,14 +98,23 @@ func Register(c fiber.Ctx) error {
 		})
 	}
 
-	if isUserExists(userData.Username) {
+	if wait := handler.loginGuard.AttemptRegistration(c.IP()); wait > 0 {
+		return tooManyAttempts(c, wait)
+	}
+
+	user, err := handler.accountService.Register(userData.Username, userData.Password)
+
+	if errors.Is(err, services.ErrUsernameTaken) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "User already exists",
 		})
 	}
 
-	hashedPassword := utils.GeneratePassword(userData.Password)
-	user, err := CreateUser(userData.Username, hashedPassword)
+	if errors.Is(err, services.ErrUsernameReserved) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
 
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -82,7 +122,7 @@ func Register(c fiber.Ctx) error {
 		})
 	}
 
-	token, err := createToken(user)
+	tokens, err := handler.sessionService.SignIn(*user, sessionClient(c))
 
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -90,16 +130,7 @@ func Register(c fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Success registration",
-		"token":   token,
-	})
-}
-
-func isUserExists(username string) bool {
-	_, err := getUserByUsername(username)
-
-	return err == nil
+	return tokensResponse(c, "Success registration", tokens)
 }
 
 func validateUserData(c fiber.Ctx) (*LoginInput, error) {
@@ -122,16 +153,121 @@ func validateUserData(c fiber.Ctx) (*LoginInput, error) {
 	return input, nil
 }
 
-func createToken(u models.User) (t string, err error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["sub"] = u.ID
-	claims["exp"] = time.Now().Add(time.Hour * 72).Unix()
-	t, err = token.SignedString([]byte(config.Config("JWT_SECRET")))
+// Refresh rotates a refresh token into a new access and refresh token pair.
+func (handler *AuthHandler) Refresh(c fiber.Ctx) error {
+	input := new(RefreshInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if input.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "refresh token is required",
+		})
+	}
+
+	tokens, err := handler.sessionService.Refresh(input.RefreshToken, sessionClient(c))
+
+	if errors.Is(err, services.ErrInvalidRefreshToken) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
 
 	if err != nil {
-		return "", err
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return tokensResponse(c, "", tokens)
+}
+
+// Logout ends the current session on this device.
+func (handler *AuthHandler) Logout(c fiber.Ctx) error {
+	sessionId, err := GetSessionID(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.sessionService.SignOut(sessionId); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Logged out",
+	})
+}
+
+// LogoutAll ends every session of the user on every device.
+func (handler *AuthHandler) LogoutAll(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.sessionService.SignOutEverywhere(authUser.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Logged out on all devices",
+	})
+}
+
+// GetSessionID is the session the request's access token belongs to.
+func GetSessionID(c fiber.Ctx) (uint, error) {
+	token := jwtware.FromContext(c)
+
+	if token == nil {
+		return 0, fiber.ErrUnauthorized
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	sessionId, ok := claims["sid"].(float64)
+
+	if !ok {
+		return 0, fiber.ErrUnauthorized
+	}
+
+	return uint(sessionId), nil
+}
+
+func tooManyAttempts(c fiber.Ctx, wait time.Duration) error {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"message": fmt.Sprintf("Too many attempts, try again in %d seconds", seconds),
+	})
+}
+
+func sessionClient(c fiber.Ctx) services.Client {
+	return services.Client{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IP:        c.IP(),
+	}
+}
+
+func tokensResponse(c fiber.Ctx, message string, tokens *services.Tokens) error {
+	response := fiber.Map{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(time.Until(tokens.ExpiresAt).Seconds()),
+	}
+
+	if message != "" {
+		response["message"] = message
 	}
 
-	return t, nil
+	return c.JSON(response)
 }
@@ -1,18 +1,22 @@
 package handlers
 
 import (
-	"app/database"
 	"app/models"
-	"context"
+	"app/services"
 
 	"github.com/gofiber/fiber/v3"
-	"gorm.io/gorm"
 )
 
-func GetCurrencies(c fiber.Ctx) error {
-	ctx := context.Background()
+type CurrencyHandler struct {
+	currencyService *services.CurrencyService
+}
+
+func NewCurrencyHandler(currencyService *services.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{currencyService: currencyService}
+}
 
-	currencies, err := gorm.G[models.Currency](database.DB).Find(ctx)
+func (handler *CurrencyHandler) GetCurrencies(c fiber.Ctx) error {
+	currencies, err := handler.currencyService.List()
 
 	if err != nil {
 		return c.JSON(fiber.Map{
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"app/http/responses"
+	"app/services"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type FairnessHandler struct {
+	fairnessService *services.FairnessService
+}
+
+func NewFairnessHandler(fairnessService *services.FairnessService) *FairnessHandler {
+	return &FairnessHandler{fairnessService: fairnessService}
+}
+
+func (handler *FairnessHandler) Verify(c fiber.Ctx) error {
+	verification, err := handler.fairnessService.Verify(c.Params("code"))
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(responses.NewVerificationResource(*verification))
+}
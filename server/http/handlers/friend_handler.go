@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/services"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type FriendHandler struct {
+	friendService *services.FriendService
+}
+
+func NewFriendHandler(friendService *services.FriendService) *FriendHandler {
+	return &FriendHandler{friendService: friendService}
+}
+
+func (handler *FriendHandler) ListFriends(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	friends, err := handler.friendService.ListFriends(authUser)
+
+	if err != nil {
+		return friendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": responses.NewFriendResources(friends),
+	})
+}
+
+func (handler *FriendHandler) ListRequests(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	requests, err := handler.friendService.ListPending(authUser)
+
+	if err != nil {
+		return friendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": responses.NewPendingRequestsResource(authUser.ID, requests),
+	})
+}
+
+func (handler *FriendHandler) SendRequest(c fiber.Ctx) error {
+	input := new(inputs.FriendRequestInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	request, err := handler.friendService.SendRequest(authUser, input.Username)
+
+	if err != nil {
+		return friendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": responses.NewFriendRequestResource(*request),
+	})
+}
+
+func (handler *FriendHandler) AcceptRequest(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	request, err := handler.friendService.Accept(authUser, fiber.Params[uint](c, "id"))
+
+	if err != nil {
+		return friendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Friend request accepted",
+		"data":    responses.NewFriendRequestResource(*request),
+	})
+}
+
+func (handler *FriendHandler) DeclineRequest(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	request, err := handler.friendService.Decline(authUser, fiber.Params[uint](c, "id"))
+
+	if err != nil {
+		return friendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Friend request declined",
+		"data":    responses.NewFriendRequestResource(*request),
+	})
+}
+
+func (handler *FriendHandler) CancelRequest(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.friendService.Cancel(authUser, fiber.Params[uint](c, "id")); err != nil {
+		return friendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Friend request cancelled",
+	})
+}
+
+func (handler *FriendHandler) RemoveFriend(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.friendService.Remove(authUser, fiber.Params[uint](c, "id")); err != nil {
+		return friendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Friend removed",
+	})
+}
+
+func friendError(c fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	message := "Internal Server Error"
+
+	switch {
+	case errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrFriendRequestNotFound),
+		errors.Is(err, services.ErrNotFriendsYet):
+		status, message = fiber.StatusNotFound, err.Error()
+	case errors.Is(err, services.ErrFriendRequestExists),
+		errors.Is(err, services.ErrFriendRequestHandled),
+		errors.Is(err, services.ErrAlreadyFriends):
+		status, message = fiber.StatusConflict, err.Error()
+	case errors.Is(err, services.ErrCannotFriendSelf):
+		status, message = fiber.StatusBadRequest, err.Error()
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"message": message,
+	})
+}
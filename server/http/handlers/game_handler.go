@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"app/farkle"
 	"app/http/inputs"
 	"app/http/responses"
+	"app/realtime"
 	"app/services"
+	"errors"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
@@ -11,10 +14,11 @@ import (
 
 type GameHandler struct {
 	gameService *services.GameService
+	hub         *realtime.Hub
 }
 
-func NewGameHandler(gameService *services.GameService) *GameHandler {
-	return &GameHandler{gameService: gameService}
+func NewGameHandler(gameService *services.GameService, hub *realtime.Hub) *GameHandler {
+	return &GameHandler{gameService: gameService, hub: hub}
 }
 
 func (handler *GameHandler) CreateGame(c fiber.Ctx) error {
@@ -35,6 +39,8 @@ func (handler *GameHandler) CreateGame(c fiber.Ctx) error {
 		})
 	}
 
+	input.Normalize()
+
 	// custom validation
 	if err := input.Validate(); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -58,3 +64,336 @@ func (handler *GameHandler) CreateGame(c fiber.Ctx) error {
 
 	return c.JSON(responses.NewGameResource(*game))
 }
+
+func (handler *GameHandler) ScoreSelection(c fiber.Ctx) error {
+	input := new(inputs.ScoreInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	result, err := handler.gameService.ScoreSelection(c.Params("code"), input.Dice)
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(result)
+}
+
+func (handler *GameHandler) JoinGame(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := handler.gameService.JoinGame(authUser, c.Params("code"))
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *GameHandler) CancelGame(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.gameService.CancelGame(authUser, c.Params("code")); err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Game cancelled, stakes refunded",
+	})
+}
+
+func (handler *GameHandler) LeaveGame(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.gameService.LeaveGame(authUser, c.Params("code")); err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Left the game, stake refunded",
+	})
+}
+
+func (handler *GameHandler) ForfeitGame(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := handler.gameService.ForfeitGame(authUser, c.Params("code")); err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Game forfeited",
+	})
+}
+
+func (handler *GameHandler) AddBot(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	input := new(inputs.AddBotInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	snapshot, err := handler.gameService.AddBot(authUser, c.Params("code"), input.Strategy)
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *GameHandler) SetLoadout(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	input := new(inputs.LoadoutInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	snapshot, err := handler.gameService.SetLoadout(authUser, c.Params("code"), *input)
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *GameHandler) StartGame(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := handler.gameService.StartGame(authUser, c.Params("code"))
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *GameHandler) Ready(c fiber.Ctx) error {
+	return handler.setReady(c, true)
+}
+
+func (handler *GameHandler) Unready(c fiber.Ctx) error {
+	return handler.setReady(c, false)
+}
+
+func (handler *GameHandler) setReady(c fiber.Ctx, ready bool) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := handler.gameService.SetReady(authUser, c.Params("code"), ready)
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *GameHandler) KickPlayer(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := handler.gameService.KickPlayer(authUser, c.Params("code"), fiber.Params[uint](c, "id"))
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *GameHandler) SetSeats(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	input := new(inputs.SeatsInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	snapshot, err := handler.gameService.SetSeats(authUser, c.Params("code"), input.Order)
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *GameHandler) OpenToSpectators(c fiber.Ctx) error {
+	return handler.setSpectating(c, true)
+}
+
+func (handler *GameHandler) CloseToSpectators(c fiber.Ctx) error {
+	return handler.setSpectating(c, false)
+}
+
+func (handler *GameHandler) setSpectating(c fiber.Ctx, allow bool) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := handler.gameService.SetSpectating(authUser, c.Params("code"), allow)
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+// gameState renders a game with the number of spectators watching it live.
+func gameState(hub *realtime.Hub, snapshot *services.GameSnapshot) responses.GameStateResource {
+	resource := responses.NewGameStateResource(*snapshot.Game, snapshot.Players)
+	resource.Spectators = hub.Spectators(snapshot.Game.Code)
+
+	return resource
+}
+
+// gameErrorStatus maps game and turn errors to an HTTP status code.
+func gameErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrGameNotFound),
+		errors.Is(err, services.ErrPlayerNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, services.ErrNotCreator),
+		errors.Is(err, services.ErrNotAPlayer),
+		errors.Is(err, services.ErrNotFriends),
+		errors.Is(err, services.ErrNotFriendsToWatch),
+		errors.Is(err, services.ErrSpectatingClosed),
+		errors.Is(err, services.ErrNotOwned),
+		errors.Is(err, farkle.ErrNotYourTurn):
+		return fiber.StatusForbidden
+	case errors.Is(err, services.ErrGameNotStarted),
+		errors.Is(err, services.ErrGameAlreadyStarted),
+		errors.Is(err, services.ErrGameCancelled),
+		errors.Is(err, services.ErrAlreadyJoined),
+		errors.Is(err, services.ErrGameFull),
+		errors.Is(err, services.ErrCreatorCannotLeave),
+		errors.Is(err, services.ErrAlreadyForfeited),
+		errors.Is(err, services.ErrNotEnoughPlayers),
+		errors.Is(err, services.ErrCannotKickCreator),
+		errors.Is(err, services.ErrSeatsDrawn),
+		errors.Is(err, farkle.ErrGameFinished),
+		errors.Is(err, farkle.ErrMustRoll),
+		errors.Is(err, farkle.ErrMustSetAside),
+		errors.Is(err, farkle.ErrNothingToBank),
+		errors.Is(err, farkle.ErrBelowOpening),
+		errors.Is(err, farkle.ErrBadgeUnavailable),
+		errors.Is(err, farkle.ErrNotEnoughPlayers):
+		return fiber.StatusConflict
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return fiber.StatusBadRequest
+	case errors.Is(err, farkle.ErrDiceNotOnTable),
+		errors.Is(err, farkle.ErrInvalidSelection),
+		errors.Is(err, services.ErrInvalidSeatOrder):
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
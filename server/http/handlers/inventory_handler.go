@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/loadout"
+	"app/services"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type InventoryHandler struct {
+	inventoryService *services.InventoryService
+}
+
+func NewInventoryHandler(inventoryService *services.InventoryService) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService}
+}
+
+func (handler *InventoryHandler) GetDice(c fiber.Ctx) error {
+	data := make([]responses.DieResource, 0, len(loadout.Dice))
+
+	for _, die := range loadout.Dice {
+		data = append(data, responses.NewDieResource(die))
+	}
+
+	return c.JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (handler *InventoryHandler) GetBadges(c fiber.Ctx) error {
+	data := make([]responses.BadgeResource, 0, len(loadout.Badges))
+
+	for _, badge := range loadout.Badges {
+		data = append(data, responses.NewBadgeResource(badge))
+	}
+
+	return c.JSON(fiber.Map{
+		"data": data,
+	})
+}
+
+func (handler *InventoryHandler) GetInventory(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	items, err := handler.inventoryService.List(authUser)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": responses.NewInventoryResource(items),
+	})
+}
+
+func (handler *InventoryHandler) Purchase(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	input := new(inputs.PurchaseInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	input.Normalize()
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	items, err := handler.inventoryService.Purchase(authUser, *input)
+
+	if errors.Is(err, services.ErrInsufficientFunds) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": responses.NewInventoryResource(items),
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/services"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *services.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService *services.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{leaderboardService: leaderboardService}
+}
+
+func (handler *LeaderboardHandler) GetLeaderboard(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	input := new(inputs.LeaderboardInput)
+
+	if err := c.Bind().Query(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	input.Normalize()
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	board, err := handler.leaderboardService.Rank(authUser, *input)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(responses.NewLeaderboardResource(input.Metric, input.Window, board.Since, board.Top, board.Me))
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/services"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type LedgerHandler struct {
+	ledgerService *services.LedgerService
+}
+
+func NewLedgerHandler(ledgerService *services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+func (handler *LedgerHandler) GetTransactions(c fiber.Ctx) error {
+	page := new(inputs.PaginationInput)
+
+	if err := c.Bind().Query(page); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	page.Normalize()
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	entries, total, err := handler.ledgerService.History(authUser, *page)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(responses.NewTransactionsResponse(entries, *page, total))
+}
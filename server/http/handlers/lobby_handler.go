@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/services"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type LobbyHandler struct {
+	lobbyService *services.LobbyService
+}
+
+func NewLobbyHandler(lobbyService *services.LobbyService) *LobbyHandler {
+	return &LobbyHandler{lobbyService: lobbyService}
+}
+
+func (handler *LobbyHandler) ListGames(c fiber.Ctx) error {
+	input := new(inputs.ListGamesInput)
+
+	if err := c.Bind().Query(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	input.Normalize()
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	games, nextCursor, err := handler.lobbyService.ListOpenGames(*input)
+
+	if errors.Is(err, services.ErrInvalidCursor) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(responses.NewLobbyResponse(games, nextCursor))
+}
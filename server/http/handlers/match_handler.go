@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/services"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type MatchHandler struct {
+	matchService *services.MatchService
+}
+
+func NewMatchHandler(matchService *services.MatchService) *MatchHandler {
+	return &MatchHandler{matchService: matchService}
+}
+
+func (handler *MatchHandler) GetProfileMatches(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	return handler.matches(c, authUser.ID)
+}
+
+func (handler *MatchHandler) GetUserMatches(c fiber.Ctx) error {
+	return handler.matches(c, fiber.Params[uint](c, "id"))
+}
+
+func (handler *MatchHandler) matches(c fiber.Ctx, userId uint) error {
+	input := new(inputs.MatchHistoryInput)
+
+	if err := c.Bind().Query(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	input.Normalize()
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	games, total, err := handler.matchService.History(userId, *input)
+
+	if errors.Is(err, services.ErrUserNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Internal Server Error",
+		})
+	}
+
+	return c.JSON(responses.NewMatchesResponse(userId, games, input.PaginationInput, total))
+}
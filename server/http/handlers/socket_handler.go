@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"app/realtime"
+	"app/services"
+	"errors"
+	"strings"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+type SocketHandler struct {
+	gameService *services.GameService
+	turnService *services.TurnService
+	hub         *realtime.Hub
+	upgrader    websocket.FastHTTPUpgrader
+}
+
+func NewSocketHandler(gameService *services.GameService, turnService *services.TurnService, hub *realtime.Hub) *SocketHandler {
+	return &SocketHandler{
+		gameService: gameService,
+		turnService: turnService,
+		hub:         hub,
+		upgrader: websocket.FastHTTPUpgrader{
+			// sockets authenticate with a bearer token, not cookies, so any origin is fine
+			CheckOrigin: func(ctx *fasthttp.RequestCtx) bool { return true },
+		},
+	}
+}
+
+// Connect upgrades the request to a WebSocket subscribed to the game's
+// events. Every (re)connection starts with a full state snapshot. Users
+// without a seat connect as spectators when the game lets them watch.
+// They get the same events; acting goes through the REST API, which only
+// takes players.
+func (handler *SocketHandler) Connect(c fiber.Ctx) error {
+	if !websocket.FastHTTPIsWebSocketUpgrade(c.RequestCtx()) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{
+			"message": "WebSocket upgrade required",
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := handler.turnService.GetState(authUser, c.Params("code"))
+	spectator := errors.Is(err, services.ErrNotAPlayer)
+
+	if spectator {
+		snapshot, err = handler.gameService.Watch(authUser, c.Params("code"))
+	}
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	// the fiber context is released once the handler returns, so copy what the socket needs
+	code := strings.Clone(snapshot.Game.Code)
+	userId := authUser.ID
+	state := gameState(handler.hub, snapshot)
+
+	return handler.upgrader.Upgrade(c.RequestCtx(), func(conn *websocket.Conn) {
+		if spectator {
+			handler.hub.Watch(conn, code, userId, state)
+		} else {
+			handler.hub.Serve(conn, code, userId, state)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"app/http/inputs"
+	"app/http/responses"
+	"app/realtime"
+	"app/services"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type TurnHandler struct {
+	turnService *services.TurnService
+	hub         *realtime.Hub
+}
+
+func NewTurnHandler(turnService *services.TurnService, hub *realtime.Hub) *TurnHandler {
+	return &TurnHandler{turnService: turnService, hub: hub}
+}
+
+func (handler *TurnHandler) GetState(c fiber.Ctx) error {
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := handler.turnService.GetState(authUser, c.Params("code"))
+
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(gameState(handler.hub, snapshot))
+}
+
+func (handler *TurnHandler) Roll(c fiber.Ctx) error {
+	input := new(inputs.RollInput)
+
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.turnService.Roll(authUser, c.Params("code"), input.ClientSeed)
+
+	return handler.turnResponse(c, result, err)
+}
+
+func (handler *TurnHandler) SetAside(c fiber.Ctx) error {
+	input := new(inputs.SetAsideInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.turnService.SetAside(authUser, c.Params("code"), input.Dice)
+
+	return handler.turnResponse(c, result, err)
+}
+
+func (handler *TurnHandler) Bank(c fiber.Ctx) error {
+	input := new(inputs.BankInput)
+
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.turnService.Bank(authUser, c.Params("code"), input.Dice)
+
+	return handler.turnResponse(c, result, err)
+}
+
+func (handler *TurnHandler) Reroll(c fiber.Ctx) error {
+	input := new(inputs.RerollInput)
+
+	if err := c.Bind().Body(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	if err := input.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	authUser, err := GetAuthUser(c)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := handler.turnService.Reroll(authUser, c.Params("code"), input.Die, input.ClientSeed)
+
+	return handler.turnResponse(c, result, err)
+}
+
+func (handler *TurnHandler) turnResponse(c fiber.Ctx, result *services.TurnResult, err error) error {
+	if err != nil {
+		return c.Status(gameErrorStatus(err)).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+
+	resource := responses.NewTurnResource(result.Outcome, *result.Snapshot.Game, result.Snapshot.Players)
+	resource.State.Spectators = handler.hub.Spectators(result.Snapshot.Game.Code)
+
+	return c.JSON(resource)
+}
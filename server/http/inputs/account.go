@@ -0,0 +1,48 @@
+package inputs
+
+import "errors"
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (input ChangePasswordInput) Validate() error {
+	if input.CurrentPassword == "" {
+		return errors.New("current password is required")
+	}
+
+	return validatePassword(input.NewPassword)
+}
+
+type RenameInput struct {
+	Username string `json:"username"`
+}
+
+func (input RenameInput) Validate() error {
+	if len(input.Username) < 3 || len(input.Username) > 255 {
+		return errors.New("username must be between 3 and 255 characters")
+	}
+
+	return nil
+}
+
+type DeleteAccountInput struct {
+	Password string `json:"password"`
+}
+
+func (input DeleteAccountInput) Validate() error {
+	if input.Password == "" {
+		return errors.New("password is required")
+	}
+
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < 6 || len(password) > 50 {
+		return errors.New("password must be between 6 and 50 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package inputs
+
+import "fmt"
+
+const (
+	StrategyCautious = "cautious"
+	StrategyGreedy   = "greedy"
+	StrategyOptimal  = "optimal"
+)
+
+// Strategies are every strategy a bot can play.
+var Strategies = []string{StrategyCautious, StrategyGreedy, StrategyOptimal}
+
+type AddBotInput struct {
+	Strategy string `json:"strategy"`
+}
+
+func (input AddBotInput) Validate() error {
+	switch input.Strategy {
+	case StrategyCautious, StrategyGreedy, StrategyOptimal:
+		return nil
+	default:
+		return fmt.Errorf("invalid strategy %s", input.Strategy)
+	}
+}
@@ -0,0 +1,15 @@
+package inputs
+
+import "errors"
+
+type FriendRequestInput struct {
+	Username string `json:"username" validate:"required,min=3,max=255"`
+}
+
+func (input FriendRequestInput) Validate() error {
+	if len(input.Username) < 3 || len(input.Username) > 255 {
+		return errors.New("username must be between 3 and 255 characters")
+	}
+
+	return nil
+}
@@ -1,7 +1,6 @@
 package inputs
 
 import (
-	"app/database"
 	"errors"
 	"fmt"
 )
@@ -12,21 +11,68 @@ const (
 	ByLink      = "link"
 )
 
+const (
+	SeatingRandom = "random"
+	SeatingHost   = "host"
+)
+
+const (
+	MinPlayersLimit = 2
+	MaxPlayersLimit = 6
+)
+
 const WinningPointsLimit = 20000
 const WinningPointsMinimum = 3000
 
+// Turn and game time limits in seconds. Zero turns a limit off.
+const (
+	MinTurnSeconds = 10
+	MaxTurnSeconds = 600
+	MinGameSeconds = 60
+	MaxGameSeconds = 7200
+)
+
 type CreateGameInput struct {
 	CurrencyID    uint   `json:"currency_id" validate:"required,int,gt:0"`
 	Bet           uint   `json:"bet" validate:"required,int,gt:0"`
 	WinningPoints uint   `json:"winning_points" validate:"required,int,gt:0"`
 	JoinType      string `json:"join_type" validate:"required,string"`
+	// MinPlayers and MaxPlayers bound the table; left out it seats two.
+	MinPlayers uint       `json:"min_players"`
+	MaxPlayers uint       `json:"max_players"`
+	Seating    string     `json:"seating"`
+	Rules      RulesInput `json:"rules"`
+	// AllowSpectators opens the game to spectators; left out it is open.
+	AllowSpectators *bool `json:"allow_spectators"`
+	// TurnSeconds and GameSeconds are the turn limit and each player's time
+	// bank; left out the game is not timed.
+	TurnSeconds *uint `json:"turn_seconds"`
+	GameSeconds *uint `json:"game_seconds"`
 }
 
-func (input CreateGameInput) Validate() error {
-	if !input.isCurrencyIsValid() {
-		return errors.New("invalid currency")
+// Normalize fills in the default table size, seating, rules and spectating.
+func (input *CreateGameInput) Normalize() {
+	input.Rules.Normalize()
+
+	if input.MinPlayers == 0 {
+		input.MinPlayers = MinPlayersLimit
+	}
+
+	if input.MaxPlayers == 0 {
+		input.MaxPlayers = max(input.MinPlayers, MinPlayersLimit)
+	}
+
+	if input.Seating == "" {
+		input.Seating = SeatingRandom
+	}
+
+	if input.AllowSpectators == nil {
+		allow := true
+		input.AllowSpectators = &allow
 	}
+}
 
+func (input CreateGameInput) Validate() error {
 	if !input.isValidJoinType() {
 		return fmt.Errorf("invalid join type %s", input.JoinType)
 	}
@@ -39,22 +85,31 @@ func (input CreateGameInput) Validate() error {
 		return fmt.Errorf("winning points must be at least %d points", WinningPointsMinimum)
 	}
 
-	return nil
-}
+	if input.MinPlayers < MinPlayersLimit || input.MaxPlayers > MaxPlayersLimit {
+		return fmt.Errorf("a table seats between %d and %d players", MinPlayersLimit, MaxPlayersLimit)
+	}
+
+	if input.MinPlayers > input.MaxPlayers {
+		return errors.New("min players cannot be greater than max players")
+	}
 
-func (input CreateGameInput) isCurrencyIsValid() bool {
-	var exists bool
+	if input.Seating != SeatingRandom && input.Seating != SeatingHost {
+		return fmt.Errorf("invalid seating %s", input.Seating)
+	}
 
-	err := database.DB.Raw(
-		"SELECT EXISTS (SELECT 1 FROM currencies WHERE id = ?)",
-		input.CurrencyID,
-	).Scan(&exists).Error
+	if err := input.Rules.Validate(); err != nil {
+		return err
+	}
 
-	if err != nil {
-		return false
+	if !withinLimit(input.TurnSeconds, MinTurnSeconds, MaxTurnSeconds) {
+		return fmt.Errorf("turn time must be off or between %d and %d seconds", MinTurnSeconds, MaxTurnSeconds)
+	}
+
+	if !withinLimit(input.GameSeconds, MinGameSeconds, MaxGameSeconds) {
+		return fmt.Errorf("game time must be off or between %d and %d seconds", MinGameSeconds, MaxGameSeconds)
 	}
 
-	return exists
+	return nil
 }
 
 func (input CreateGameInput) isValidJoinType() bool {
@@ -73,3 +128,8 @@ func (input CreateGameInput) lteWinningPointsLimit() bool {
 func (input CreateGameInput) gteWinningPointsMinimum() bool {
 	return input.WinningPoints >= WinningPointsMinimum
 }
+
+// withinLimit accepts a time limit that is unset, off or in range.
+func withinLimit(seconds *uint, min, max uint) bool {
+	return seconds == nil || *seconds == 0 || *seconds >= min && *seconds <= max
+}
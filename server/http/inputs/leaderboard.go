@@ -0,0 +1,76 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	MetricWinnings = "winnings"
+	MetricWins     = "wins"
+	MetricWinRate  = "win_rate"
+	MetricBestTurn = "best_turn"
+)
+
+const (
+	WindowAllTime = "all"
+	WindowMonthly = "month"
+	WindowWeekly  = "week"
+)
+
+const (
+	DefaultLeaderboardLimit = 10
+	// DefaultWinRateMinGames keeps players with a handful of lucky games off the win rate board.
+	DefaultWinRateMinGames = 10
+)
+
+type LeaderboardInput struct {
+	Metric     string `query:"metric"`
+	Window     string `query:"window"`
+	CurrencyID uint   `query:"currency_id"`
+	MinGames   uint   `query:"min_games"`
+	Limit      int    `query:"limit"`
+}
+
+// Normalize fills in the default metric, window and limits.
+func (input *LeaderboardInput) Normalize() {
+	if input.Metric == "" {
+		input.Metric = MetricWins
+	}
+
+	if input.Window == "" {
+		input.Window = WindowAllTime
+	}
+
+	if input.Metric == MetricWinRate && input.MinGames == 0 {
+		input.MinGames = DefaultWinRateMinGames
+	}
+
+	if input.Limit < 1 {
+		input.Limit = DefaultLeaderboardLimit
+	}
+
+	if input.Limit > MaxPerPage {
+		input.Limit = MaxPerPage
+	}
+}
+
+func (input LeaderboardInput) Validate() error {
+	switch input.Metric {
+	case MetricWinnings, MetricWins, MetricWinRate, MetricBestTurn:
+	default:
+		return fmt.Errorf("invalid metric %s", input.Metric)
+	}
+
+	switch input.Window {
+	case WindowAllTime, WindowMonthly, WindowWeekly:
+	default:
+		return fmt.Errorf("invalid window %s", input.Window)
+	}
+
+	if input.Metric == MetricWinnings && input.CurrencyID == 0 {
+		return errors.New("currency is required to rank by winnings")
+	}
+
+	return nil
+}
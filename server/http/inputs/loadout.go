@@ -0,0 +1,76 @@
+package inputs
+
+import (
+	"app/farkle"
+	"app/loadout"
+	"app/models"
+	"errors"
+	"fmt"
+)
+
+// LoadoutInput is the dice a player brings to a game, one slug per slot,
+// and an optional badge. No dice means six standard dice.
+type LoadoutInput struct {
+	Dice  []string `json:"dice"`
+	Badge string   `json:"badge"`
+}
+
+func (input LoadoutInput) Validate() error {
+	if len(input.Dice) != 0 && len(input.Dice) != farkle.DiceCount {
+		return fmt.Errorf("a loadout has exactly %d dice", farkle.DiceCount)
+	}
+
+	for _, slug := range input.Dice {
+		if _, ok := loadout.FindDie(slug); !ok {
+			return fmt.Errorf("unknown die %s", slug)
+		}
+	}
+
+	if _, ok := loadout.FindBadge(input.Badge); input.Badge != "" && !ok {
+		return fmt.Errorf("unknown badge %s", input.Badge)
+	}
+
+	return nil
+}
+
+const MaxPurchaseQuantity = 6
+
+type PurchaseInput struct {
+	Kind     string `json:"kind"`
+	Slug     string `json:"slug"`
+	Quantity uint   `json:"quantity"`
+}
+
+// Normalize buys a single item when no quantity is given.
+func (input *PurchaseInput) Normalize() {
+	if input.Quantity == 0 {
+		input.Quantity = 1
+	}
+}
+
+func (input PurchaseInput) Validate() error {
+	switch input.Kind {
+	case models.ItemDie:
+		die, ok := loadout.FindDie(input.Slug)
+
+		if !ok {
+			return fmt.Errorf("unknown die %s", input.Slug)
+		}
+
+		if die.Slug == loadout.StandardDie {
+			return errors.New("standard dice are free")
+		}
+	case models.ItemBadge:
+		if _, ok := loadout.FindBadge(input.Slug); !ok {
+			return fmt.Errorf("unknown badge %s", input.Slug)
+		}
+	default:
+		return fmt.Errorf("invalid kind %s", input.Kind)
+	}
+
+	if input.Quantity > MaxPurchaseQuantity {
+		return fmt.Errorf("at most %d items can be bought at once", MaxPurchaseQuantity)
+	}
+
+	return nil
+}
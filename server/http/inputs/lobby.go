@@ -0,0 +1,65 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	SortNewest        = "newest"
+	SortOldest        = "oldest"
+	SortBet           = "bet"
+	SortWinningPoints = "winning_points"
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+type ListGamesInput struct {
+	CurrencyID uint   `query:"currency_id"`
+	MinBet     uint   `query:"min_bet"`
+	MaxBet     uint   `query:"max_bet"`
+	Sort       string `query:"sort"`
+	Order      string `query:"order"`
+	Cursor     string `query:"cursor"`
+	Limit      int    `query:"limit"`
+}
+
+// Normalize fills in the default sort and page size.
+func (input *ListGamesInput) Normalize() {
+	if input.Sort == "" {
+		input.Sort = SortNewest
+	}
+
+	if input.Order == "" {
+		input.Order = OrderDesc
+	}
+
+	if input.Limit < 1 {
+		input.Limit = DefaultPerPage
+	}
+
+	if input.Limit > MaxPerPage {
+		input.Limit = MaxPerPage
+	}
+}
+
+func (input ListGamesInput) Validate() error {
+	switch input.Sort {
+	case SortNewest, SortOldest, SortBet, SortWinningPoints:
+	default:
+		return fmt.Errorf("invalid sort %s", input.Sort)
+	}
+
+	if input.Order != OrderAsc && input.Order != OrderDesc {
+		return fmt.Errorf("invalid order %s", input.Order)
+	}
+
+	if input.MaxBet > 0 && input.MinBet > input.MaxBet {
+		return errors.New("min bet cannot be greater than max bet")
+	}
+
+	return nil
+}
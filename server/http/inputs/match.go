@@ -0,0 +1,22 @@
+package inputs
+
+import "fmt"
+
+const (
+	ResultWin  = "win"
+	ResultLoss = "loss"
+)
+
+type MatchHistoryInput struct {
+	PaginationInput
+	Result string `query:"result"`
+}
+
+func (input MatchHistoryInput) Validate() error {
+	switch input.Result {
+	case "", ResultWin, ResultLoss:
+		return nil
+	default:
+		return fmt.Errorf("invalid result %s", input.Result)
+	}
+}
@@ -0,0 +1,30 @@
+package inputs
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+type PaginationInput struct {
+	Page    int `query:"page"`
+	PerPage int `query:"per_page"`
+}
+
+// Normalize clamps the page and page size into their allowed ranges.
+func (input *PaginationInput) Normalize() {
+	if input.Page < 1 {
+		input.Page = 1
+	}
+
+	if input.PerPage < 1 {
+		input.PerPage = DefaultPerPage
+	}
+
+	if input.PerPage > MaxPerPage {
+		input.PerPage = MaxPerPage
+	}
+}
+
+func (input PaginationInput) Offset() int {
+	return (input.Page - 1) * input.PerPage
+}
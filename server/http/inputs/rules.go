@@ -0,0 +1,61 @@
+package inputs
+
+import (
+	"app/farkle"
+	"fmt"
+)
+
+const (
+	MaxOpeningScore  = 1000
+	MaxFarklePenalty = 1000
+	// RulesStep is the granularity of score settings: nothing scores in smaller steps.
+	RulesStep = 50
+)
+
+// RulesInput are the house rules of a new game. Anything left out plays by
+// the standard rules.
+type RulesInput struct {
+	OpeningScore     uint   `json:"opening_score"`
+	FarklePenalty    uint   `json:"farkle_penalty"`
+	OfAKind          string `json:"of_a_kind"`
+	PartialStraights *bool  `json:"partial_straights"`
+	CatchUp          bool   `json:"catch_up"`
+}
+
+// Normalize fills in the standard rules for anything left out.
+func (input *RulesInput) Normalize() {
+	if input.OfAKind == "" {
+		input.OfAKind = farkle.StandardRules.OfAKind
+	}
+
+	if input.PartialStraights == nil {
+		partial := farkle.StandardRules.PartialStraights
+		input.PartialStraights = &partial
+	}
+}
+
+func (input RulesInput) Validate() error {
+	if input.OpeningScore > MaxOpeningScore || input.OpeningScore%RulesStep != 0 {
+		return fmt.Errorf("opening score must be a multiple of %d up to %d", RulesStep, MaxOpeningScore)
+	}
+
+	if input.FarklePenalty > MaxFarklePenalty || input.FarklePenalty%RulesStep != 0 {
+		return fmt.Errorf("farkle penalty must be a multiple of %d up to %d", RulesStep, MaxFarklePenalty)
+	}
+
+	if input.OfAKind != farkle.OfAKindDouble && input.OfAKind != farkle.OfAKindAdd {
+		return fmt.Errorf("invalid of a kind rule %s", input.OfAKind)
+	}
+
+	return nil
+}
+
+func (input RulesInput) Rules() farkle.Rules {
+	return farkle.Rules{
+		OpeningScore:     input.OpeningScore,
+		FarklePenalty:    input.FarklePenalty,
+		OfAKind:          input.OfAKind,
+		PartialStraights: input.PartialStraights != nil && *input.PartialStraights,
+		CatchUp:          input.CatchUp,
+	}
+}
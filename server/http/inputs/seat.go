@@ -0,0 +1,26 @@
+package inputs
+
+import "errors"
+
+// SeatsInput is the turn order the creator arranges, as player ids.
+type SeatsInput struct {
+	Order []uint `json:"order"`
+}
+
+func (input SeatsInput) Validate() error {
+	if len(input.Order) == 0 {
+		return errors.New("order is required")
+	}
+
+	seen := make(map[uint]bool, len(input.Order))
+
+	for _, id := range input.Order {
+		if seen[id] {
+			return errors.New("order lists a player more than once")
+		}
+
+		seen[id] = true
+	}
+
+	return nil
+}
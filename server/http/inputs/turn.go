@@ -0,0 +1,85 @@
+package inputs
+
+import (
+	"app/farkle"
+	"errors"
+	"fmt"
+)
+
+type SetAsideInput struct {
+	Dice []int `json:"dice" validate:"required,min=1,max=6"`
+}
+
+func (input SetAsideInput) Validate() error {
+	if len(input.Dice) == 0 {
+		return errors.New("select at least one die")
+	}
+
+	return validateDice(input.Dice)
+}
+
+// ScoreInput is a selection of dice to score without playing it.
+type ScoreInput struct {
+	Dice []int `json:"dice" validate:"required,min=1,max=6"`
+}
+
+func (input ScoreInput) Validate() error {
+	return SetAsideInput{Dice: input.Dice}.Validate()
+}
+
+// BankInput optionally sets aside dice from the last throw before banking.
+type BankInput struct {
+	Dice []int `json:"dice" validate:"max=6"`
+}
+
+func (input BankInput) Validate() error {
+	return validateDice(input.Dice)
+}
+
+func validateDice(dice []int) error {
+	if len(dice) > farkle.DiceCount {
+		return fmt.Errorf("at most %d dice can be selected", farkle.DiceCount)
+	}
+
+	for _, d := range dice {
+		if err := validateDie(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateDie(d int) error {
+	if d != farkle.Wildcard && (d < farkle.MinFace || d > farkle.MaxFace) {
+		return fmt.Errorf("invalid die value %d", d)
+	}
+
+	return nil
+}
+
+type RollInput struct {
+	ClientSeed string `json:"client_seed" validate:"max=64"`
+}
+
+func (input RollInput) Validate() error {
+	if len(input.ClientSeed) > 64 {
+		return errors.New("client seed must be at most 64 characters")
+	}
+
+	return nil
+}
+
+// RerollInput picks the die on the table to throw again by its value.
+type RerollInput struct {
+	Die        int    `json:"die"`
+	ClientSeed string `json:"client_seed" validate:"max=64"`
+}
+
+func (input RerollInput) Validate() error {
+	if err := validateDie(input.Die); err != nil {
+		return err
+	}
+
+	return RollInput{ClientSeed: input.ClientSeed}.Validate()
+}
@@ -1,19 +1,73 @@
 package middlewares
 
 import (
-	"app/config"
+	"app/models"
 
 	jwtware "github.com/gofiber/contrib/v3/jwt"
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/extractors"
+	"github.com/golang-jwt/jwt/v5"
 )
 
-func Protected() fiber.Handler {
+// AuthUserKey is the request local the signed-in user is stored under.
+const AuthUserKey = "authUser"
+
+// Sessions resolves the user behind an access token while its session is active.
+type Sessions interface {
+	Authenticate(sessionId, userId uint) (*models.User, error)
+}
+
+// Protected accepts access tokens whose session has not been revoked.
+func Protected(secret string, sessions Sessions) fiber.Handler {
 	return jwtware.New(jwtware.Config{
-		SigningKey:   jwtware.SigningKey{Key: []byte(config.Config("JWT_SECRET"))},
-		ErrorHandler: jwtError,
+		SigningKey:     jwtware.SigningKey{Key: []byte(secret)},
+		ErrorHandler:   jwtError,
+		SuccessHandler: activeSession(sessions),
 	})
 }
 
+// ProtectedSocket accepts the token from the "token" query parameter as
+// well, since browsers cannot set headers on WebSocket requests.
+func ProtectedSocket(secret string, sessions Sessions) fiber.Handler {
+	return jwtware.New(jwtware.Config{
+		SigningKey:     jwtware.SigningKey{Key: []byte(secret)},
+		ErrorHandler:   jwtError,
+		SuccessHandler: activeSession(sessions),
+		Extractor: extractors.Chain(
+			extractors.FromAuthHeader("Bearer"),
+			extractors.FromQuery("token"),
+		),
+	})
+}
+
+func activeSession(sessions Sessions) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		token := jwtware.FromContext(c)
+		claims, _ := token.Claims.(jwt.MapClaims)
+		sessionId, hasSession := claims["sid"].(float64)
+		userId, hasUser := claims["sub"].(float64)
+
+		if !hasSession || !hasUser {
+			return sessionEnded(c)
+		}
+
+		user, err := sessions.Authenticate(uint(sessionId), uint(userId))
+
+		if err != nil {
+			return sessionEnded(c)
+		}
+
+		c.Locals(AuthUserKey, user)
+
+		return c.Next()
+	}
+}
+
+func sessionEnded(c fiber.Ctx) error {
+	return c.Status(fiber.StatusUnauthorized).
+		JSON(fiber.Map{"status": "error", "message": "Session has ended", "data": nil})
+}
+
 func jwtError(c fiber.Ctx, err error) error {
 	if err.Error() == "Missing or malformed JWT" {
 		return c.Status(fiber.StatusBadRequest).
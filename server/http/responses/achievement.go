@@ -0,0 +1,58 @@
+package responses
+
+import (
+	"app/services"
+	"time"
+)
+
+type AchievementResource struct {
+	Slug        string     `json:"slug"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Goal        uint       `json:"goal"`
+	Progress    uint       `json:"progress"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlocked_at"`
+}
+
+type AchievementsResponse struct {
+	Data     []AchievementResource `json:"data"`
+	Unlocked int                   `json:"unlocked"`
+	Total    int                   `json:"total"`
+}
+
+func NewAchievementResource(status services.AchievementStatus) AchievementResource {
+	resource := AchievementResource{
+		Slug:        status.Definition.Slug,
+		Title:       status.Definition.Title,
+		Description: status.Definition.Description,
+		Goal:        status.Definition.Goal,
+		Progress:    status.Achievement.Progress,
+		Unlocked:    status.Achievement.IsUnlocked(),
+	}
+
+	if resource.Unlocked {
+		resource.UnlockedAt = &status.Achievement.UnlockedAt
+	}
+
+	return resource
+}
+
+func NewAchievementsResponse(statuses []services.AchievementStatus) AchievementsResponse {
+	response := AchievementsResponse{
+		Data:  make([]AchievementResource, 0, len(statuses)),
+		Total: len(statuses),
+	}
+
+	for _, status := range statuses {
+		resource := NewAchievementResource(status)
+
+		if resource.Unlocked {
+			response.Unlocked++
+		}
+
+		response.Data = append(response.Data, resource)
+	}
+
+	return response
+}
@@ -0,0 +1,57 @@
+package responses
+
+import "app/services"
+
+type RollVerificationResource struct {
+	Nonce      uint     `json:"nonce"`
+	UserID     uint     `json:"user_id"`
+	ClientSeed string   `json:"client_seed"`
+	Loadout    []string `json:"loadout,omitempty"`
+	Slots      []int    `json:"slots,omitempty"`
+	Dice       []int    `json:"dice"`
+	Expected   []int    `json:"expected,omitempty"`
+	Valid      *bool    `json:"valid,omitempty"`
+}
+
+type VerificationResource struct {
+	Code            string                     `json:"code"`
+	ServerSeedHash  string                     `json:"server_seed_hash"`
+	ServerSeed      string                     `json:"server_seed,omitempty"`
+	Revealed        bool                       `json:"revealed"`
+	SeedMatchesHash *bool                      `json:"seed_matches_hash,omitempty"`
+	Rolls           []RollVerificationResource `json:"rolls"`
+}
+
+func NewVerificationResource(verification services.Verification) VerificationResource {
+	resource := VerificationResource{
+		Code:           verification.Game.Code,
+		ServerSeedHash: verification.Game.ServerSeedHash,
+		Revealed:       verification.Revealed,
+		Rolls:          make([]RollVerificationResource, 0, len(verification.Rolls)),
+	}
+
+	if verification.Revealed {
+		resource.ServerSeed = verification.Game.ServerSeed
+		resource.SeedMatchesHash = &verification.SeedMatchesHash
+	}
+
+	for _, r := range verification.Rolls {
+		item := RollVerificationResource{
+			Nonce:      r.Roll.Nonce,
+			UserID:     r.Roll.UserID,
+			ClientSeed: r.Roll.ClientSeed,
+			Loadout:    r.Loadout,
+			Slots:      r.Roll.Slots,
+			Dice:       r.Roll.Dice,
+		}
+
+		if verification.Revealed {
+			item.Expected = r.Expected
+			item.Valid = &r.Valid
+		}
+
+		resource.Rolls = append(resource.Rolls, item)
+	}
+
+	return resource
+}
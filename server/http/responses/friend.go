@@ -0,0 +1,77 @@
+package responses
+
+import (
+	"app/models"
+	"time"
+)
+
+type FriendResource struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type FriendRequestResource struct {
+	ID          uint           `json:"id"`
+	Status      string         `json:"status"`
+	Sender      FriendResource `json:"sender"`
+	Receiver    FriendResource `json:"receiver"`
+	CreatedAt   time.Time      `json:"created_at"`
+	RespondedAt *time.Time     `json:"responded_at"`
+}
+
+type PendingRequestsResource struct {
+	Incoming []FriendRequestResource `json:"incoming"`
+	Outgoing []FriendRequestResource `json:"outgoing"`
+}
+
+func NewFriendResource(user models.User) FriendResource {
+	return FriendResource{
+		ID:       user.ID,
+		Username: user.Username,
+	}
+}
+
+func NewFriendResources(users []models.User) []FriendResource {
+	data := make([]FriendResource, 0, len(users))
+
+	for _, u := range users {
+		data = append(data, NewFriendResource(u))
+	}
+
+	return data
+}
+
+func NewFriendRequestResource(request models.FriendRequest) FriendRequestResource {
+	resource := FriendRequestResource{
+		ID:        request.ID,
+		Status:    request.Status,
+		Sender:    NewFriendResource(request.Sender),
+		Receiver:  NewFriendResource(request.Receiver),
+		CreatedAt: request.CreatedAt,
+	}
+
+	if !request.RespondedAt.IsZero() {
+		resource.RespondedAt = &request.RespondedAt
+	}
+
+	return resource
+}
+
+// NewPendingRequestsResource splits pending requests into those the user
+// received and those they sent.
+func NewPendingRequestsResource(userId uint, requests []models.FriendRequest) PendingRequestsResource {
+	resource := PendingRequestsResource{
+		Incoming: make([]FriendRequestResource, 0),
+		Outgoing: make([]FriendRequestResource, 0),
+	}
+
+	for _, r := range requests {
+		if r.ReceiverID == userId {
+			resource.Incoming = append(resource.Incoming, NewFriendRequestResource(r))
+		} else {
+			resource.Outgoing = append(resource.Outgoing, NewFriendRequestResource(r))
+		}
+	}
+
+	return resource
+}
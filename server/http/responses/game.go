@@ -1,6 +1,30 @@
 package responses
 
-import "app/models"
+import (
+	"app/farkle"
+	"app/loadout"
+	"app/models"
+	"math"
+	"time"
+)
+
+type RulesResource struct {
+	OpeningScore     uint   `json:"opening_score"`
+	FarklePenalty    uint   `json:"farkle_penalty"`
+	OfAKind          string `json:"of_a_kind"`
+	PartialStraights bool   `json:"partial_straights"`
+	CatchUp          bool   `json:"catch_up"`
+}
+
+func NewRulesResource(rules models.GameRules) RulesResource {
+	return RulesResource{
+		OpeningScore:     rules.OpeningScore,
+		FarklePenalty:    rules.FarklePenalty,
+		OfAKind:          rules.OfAKind,
+		PartialStraights: rules.PartialStraights,
+		CatchUp:          rules.CatchUp,
+	}
+}
 
 type GameResource struct {
 	ID            uint             `json:"id"`
@@ -8,16 +32,213 @@ type GameResource struct {
 	Bet           uint             `json:"bet"`
 	WinningPoints uint             `json:"winning_points"`
 	Link          string           `json:"link"`
+	MinPlayers    uint             `json:"min_players"`
+	MaxPlayers    uint             `json:"max_players"`
+	Seating       string           `json:"seating"`
+	Rules         RulesResource    `json:"rules"`
+	TurnSeconds   uint             `json:"turn_seconds"`
+	GameSeconds   uint             `json:"game_seconds"`
 	Currency      CurrencyResource `json:"currency"`
+	// AllowSpectators is whether users outside the game may watch it and
+	// Spectators how many are watching right now.
+	AllowSpectators bool `json:"allow_spectators"`
+	Spectators      uint `json:"spectators"`
 }
 
 func NewGameResource(game models.Game) GameResource {
 	return GameResource{
-		ID:            game.ID,
-		Code:          game.Code,
-		Currency:      NewCurrencyResource(game.Currency),
-		Bet:           game.Bet,
-		WinningPoints: game.WinningPoints,
-		Link:          game.Code,
+		ID:              game.ID,
+		Code:            game.Code,
+		Currency:        NewCurrencyResource(game.Currency),
+		Bet:             game.Bet,
+		WinningPoints:   game.WinningPoints,
+		Link:            game.Code,
+		MinPlayers:      game.Quorum(),
+		MaxPlayers:      game.Capacity(),
+		Seating:         game.Seating,
+		Rules:           NewRulesResource(game.Rules),
+		TurnSeconds:     game.TurnSeconds,
+		GameSeconds:     game.GameSeconds,
+		AllowSpectators: game.AllowsSpectators(),
+	}
+}
+
+type PlayerResource struct {
+	ID        uint     `json:"id"`
+	Username  string   `json:"username"`
+	Score     uint     `json:"score"`
+	IsWinner  bool     `json:"is_winner"`
+	Seat      uint     `json:"seat"`
+	Ready     bool     `json:"ready"`
+	Loadout   []string `json:"loadout,omitempty"`
+	Badge     string   `json:"badge,omitempty"`
+	BadgeUsed bool     `json:"badge_used,omitempty"`
+	Forfeited bool     `json:"forfeited,omitempty"`
+	// Farkles is how many turns in a row the player has farkled.
+	Farkles uint `json:"farkles"`
+	// TimeLeft is the seconds left in the player's time bank, when the game has one.
+	TimeLeft *uint `json:"time_left,omitempty"`
+}
+
+type GameStateResource struct {
+	Code          string           `json:"code"`
+	CreatorID     uint             `json:"creator_id"`
+	MinPlayers    uint             `json:"min_players"`
+	MaxPlayers    uint             `json:"max_players"`
+	Seating       string           `json:"seating"`
+	Rules         RulesResource    `json:"rules"`
+	Started       bool             `json:"started"`
+	Finished      bool             `json:"finished"`
+	WinningPoints uint             `json:"winning_points"`
+	Turn          uint             `json:"turn"`
+	Phase         string           `json:"phase"`
+	CurrentUserID uint             `json:"current_user_id"`
+	Dice          []int            `json:"dice"`
+	DiceTypes     []string         `json:"dice_types"`
+	DiceLeft      uint             `json:"dice_left"`
+	TurnScore     uint             `json:"turn_score"`
+	Players       []PlayerResource `json:"players"`
+	SeedHash      string           `json:"server_seed_hash"`
+	// LastRound is set once someone has reached the winning points under
+	// the catch-up rule; LastTurns are the players still to play.
+	LastRound   bool   `json:"last_round"`
+	LastTurns   []uint `json:"last_turns"`
+	TurnSeconds uint   `json:"turn_seconds"`
+	GameSeconds uint   `json:"game_seconds"`
+	// TurnDeadline is when the current turn runs out and TurnTimeLeft the
+	// seconds until then, for clients whose clock is off. Both are null
+	// when the turn is not timed.
+	TurnDeadline *time.Time `json:"turn_deadline"`
+	TurnTimeLeft *uint      `json:"turn_time_left"`
+	// AllowSpectators is whether users outside the game may watch it and
+	// Spectators how many are watching right now.
+	AllowSpectators bool `json:"allow_spectators"`
+	Spectators      uint `json:"spectators"`
+}
+
+type TurnResource struct {
+	Outcome farkle.Outcome    `json:"outcome"`
+	State   GameStateResource `json:"state"`
+}
+
+func NewGameStateResource(game models.Game, players []models.GameUser) GameStateResource {
+	resource := GameStateResource{
+		Code:            game.Code,
+		CreatorID:       game.CreatorID,
+		MinPlayers:      game.Quorum(),
+		MaxPlayers:      game.Capacity(),
+		Seating:         game.Seating,
+		Rules:           NewRulesResource(game.Rules),
+		Started:         game.IsStarted(),
+		Finished:        game.IsFinished(),
+		WinningPoints:   game.WinningPoints,
+		SeedHash:        game.ServerSeedHash,
+		TurnSeconds:     game.TurnSeconds,
+		GameSeconds:     game.GameSeconds,
+		Dice:            make([]int, 0),
+		DiceTypes:       make([]string, 0),
+		Players:         make([]PlayerResource, 0, len(players)),
+		LastTurns:       make([]uint, 0),
+		AllowSpectators: game.AllowsSpectators(),
+	}
+
+	if game.State != nil {
+		resource.Turn = game.State.Turn
+		resource.Phase = game.State.Phase
+		resource.CurrentUserID = game.State.CurrentUserID
+		resource.DiceLeft = game.State.DiceLeft
+		resource.TurnScore = game.State.TurnScore
+		resource.LastRound = game.State.LastRoundBy != 0
+
+		if game.State.LastTurns != nil {
+			resource.LastTurns = game.State.LastTurns
+		}
+
+		if game.State.Dice != nil {
+			resource.Dice = game.State.Dice
+			resource.DiceTypes = diceTypes(game.State, players)
+		}
+	}
+
+	now := time.Now()
+
+	for _, p := range players {
+		player := PlayerResource{
+			ID:        p.UserID,
+			Username:  p.User.Username,
+			Score:     p.Score,
+			IsWinner:  p.IsWinner,
+			Seat:      p.Seat,
+			Ready:     p.IsReady(),
+			Loadout:   p.Loadout,
+			Badge:     p.Badge,
+			BadgeUsed: p.BadgeUsed,
+			Forfeited: p.HasForfeited(),
+			Farkles:   p.Farkles,
+		}
+
+		var deadline time.Time
+
+		if game.State != nil && game.State.CurrentUserID == p.UserID {
+			deadline = game.TurnDeadline(p)
+		}
+
+		if !deadline.IsZero() {
+			resource.TurnDeadline = &deadline
+			resource.TurnTimeLeft = secondsLeft(deadline.Sub(now))
+		}
+
+		if game.GameSeconds > 0 {
+			left := game.TimeLeft(p)
+
+			// the running turn counts against the bank of the player taking it
+			if !deadline.IsZero() {
+				left -= now.Sub(game.State.TurnStartedAt)
+			}
+
+			player.TimeLeft = secondsLeft(left)
+		}
+
+		resource.Players = append(resource.Players, player)
 	}
+
+	return resource
+}
+
+// secondsLeft rounds a remaining duration up to whole seconds.
+func secondsLeft(left time.Duration) *uint {
+	seconds := uint(math.Ceil(max(left, 0).Seconds()))
+
+	return &seconds
+}
+
+func NewTurnResource(outcome farkle.Outcome, game models.Game, players []models.GameUser) TurnResource {
+	return TurnResource{
+		Outcome: outcome,
+		State:   NewGameStateResource(game, players),
+	}
+}
+
+// diceTypes is the die slug of every die on the table, taken from the
+// current player's loadout.
+func diceTypes(state *models.GameState, players []models.GameUser) []string {
+	var slugs []string
+
+	for _, p := range players {
+		if p.UserID == state.CurrentUserID {
+			slugs = p.Loadout
+		}
+	}
+
+	types := make([]string, len(state.Dice))
+
+	for i := range types {
+		types[i] = loadout.StandardDie
+
+		if i < len(state.Slots) && state.Slots[i] < len(slugs) {
+			types[i] = slugs[state.Slots[i]]
+		}
+	}
+
+	return types
 }
@@ -0,0 +1,57 @@
+package responses
+
+import (
+	"app/repositories"
+	"time"
+)
+
+type LeaderboardEntryResource struct {
+	Rank     uint    `json:"rank"`
+	UserID   uint    `json:"user_id"`
+	Username string  `json:"username"`
+	Games    uint    `json:"games"`
+	Wins     uint    `json:"wins"`
+	Value    float64 `json:"value"`
+}
+
+type LeaderboardResource struct {
+	Metric string                     `json:"metric"`
+	Window string                     `json:"window"`
+	Since  *time.Time                 `json:"since"`
+	Data   []LeaderboardEntryResource `json:"data"`
+	Me     *LeaderboardEntryResource  `json:"me"`
+}
+
+func NewLeaderboardEntryResource(row repositories.LeaderboardRow) LeaderboardEntryResource {
+	return LeaderboardEntryResource{
+		Rank:     row.Rank,
+		UserID:   row.UserID,
+		Username: row.Username,
+		Games:    row.Games,
+		Wins:     row.Wins,
+		Value:    row.Value,
+	}
+}
+
+func NewLeaderboardResource(metric, window string, since time.Time, top []repositories.LeaderboardRow, me *repositories.LeaderboardRow) LeaderboardResource {
+	resource := LeaderboardResource{
+		Metric: metric,
+		Window: window,
+		Data:   make([]LeaderboardEntryResource, 0, len(top)),
+	}
+
+	if !since.IsZero() {
+		resource.Since = &since
+	}
+
+	for _, row := range top {
+		resource.Data = append(resource.Data, NewLeaderboardEntryResource(row))
+	}
+
+	if me != nil {
+		entry := NewLeaderboardEntryResource(*me)
+		resource.Me = &entry
+	}
+
+	return resource
+}
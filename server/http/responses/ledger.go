@@ -0,0 +1,52 @@
+package responses
+
+import (
+	"app/http/inputs"
+	"app/models"
+	"time"
+)
+
+type TransactionResource struct {
+	ID           uint             `json:"id"`
+	Delta        int64            `json:"delta"`
+	Reason       string           `json:"reason"`
+	BalanceAfter uint             `json:"balance_after"`
+	GameCode     string           `json:"game_code,omitempty"`
+	Currency     CurrencyResource `json:"currency"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+type TransactionsResponse struct {
+	Data []TransactionResource `json:"data"`
+	Meta PaginationMeta        `json:"meta"`
+}
+
+func NewTransactionResource(entry models.LedgerEntry) TransactionResource {
+	resource := TransactionResource{
+		ID:           entry.ID,
+		Delta:        entry.Delta,
+		Reason:       entry.Reason,
+		BalanceAfter: entry.BalanceAfter,
+		Currency:     NewCurrencyResource(entry.Currency),
+		CreatedAt:    entry.CreatedAt,
+	}
+
+	if entry.Game != nil {
+		resource.GameCode = entry.Game.Code
+	}
+
+	return resource
+}
+
+func NewTransactionsResponse(entries []models.LedgerEntry, page inputs.PaginationInput, total int64) TransactionsResponse {
+	data := make([]TransactionResource, 0, len(entries))
+
+	for _, e := range entries {
+		data = append(data, NewTransactionResource(e))
+	}
+
+	return TransactionsResponse{
+		Data: data,
+		Meta: NewPaginationMeta(page, total),
+	}
+}
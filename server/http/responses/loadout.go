@@ -0,0 +1,66 @@
+package responses
+
+import (
+	"app/loadout"
+	"app/models"
+)
+
+type DieResource struct {
+	Slug        string     `json:"slug"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Faces       [6]int     `json:"faces"`
+	Chances     [6]float64 `json:"chances"`
+	Price       uint       `json:"price"`
+	Currency    string     `json:"currency,omitempty"`
+}
+
+type BadgeResource struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       uint   `json:"price"`
+	Currency    string `json:"currency"`
+}
+
+type InventoryItemResource struct {
+	Kind     string `json:"kind"`
+	Slug     string `json:"slug"`
+	Quantity uint   `json:"quantity"`
+}
+
+func NewDieResource(die loadout.Die) DieResource {
+	return DieResource{
+		Slug:        die.Slug,
+		Name:        die.Name,
+		Description: die.Description,
+		Faces:       die.Die.Faces,
+		Chances:     die.Chances(),
+		Price:       die.Price,
+		Currency:    die.Currency,
+	}
+}
+
+func NewBadgeResource(badge loadout.Badge) BadgeResource {
+	return BadgeResource{
+		Slug:        string(badge.Slug),
+		Name:        badge.Name,
+		Description: badge.Description,
+		Price:       badge.Price,
+		Currency:    badge.Currency,
+	}
+}
+
+func NewInventoryResource(items []models.InventoryItem) []InventoryItemResource {
+	resources := make([]InventoryItemResource, 0, len(items))
+
+	for _, item := range items {
+		resources = append(resources, InventoryItemResource{
+			Kind:     item.Kind,
+			Slug:     item.Slug,
+			Quantity: item.Quantity,
+		})
+	}
+
+	return resources
+}
@@ -0,0 +1,58 @@
+package responses
+
+import (
+	"app/models"
+	"time"
+)
+
+type CreatorResource struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type LobbyGameResource struct {
+	Code          string           `json:"code"`
+	Bet           uint             `json:"bet"`
+	WinningPoints uint             `json:"winning_points"`
+	Players       uint             `json:"players"`
+	MinPlayers    uint             `json:"min_players"`
+	MaxPlayers    uint             `json:"max_players"`
+	Currency      CurrencyResource `json:"currency"`
+	Creator       CreatorResource  `json:"creator"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type LobbyMeta struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+type LobbyResponse struct {
+	Data []LobbyGameResource `json:"data"`
+	Meta LobbyMeta           `json:"meta"`
+}
+
+func NewLobbyResponse(games []models.Game, nextCursor string) LobbyResponse {
+	data := make([]LobbyGameResource, 0, len(games))
+
+	for _, game := range games {
+		data = append(data, LobbyGameResource{
+			Code:          game.Code,
+			Bet:           game.Bet,
+			WinningPoints: game.WinningPoints,
+			Players:       game.PlayersCount,
+			MinPlayers:    game.Quorum(),
+			MaxPlayers:    game.Capacity(),
+			Currency:      NewCurrencyResource(game.Currency),
+			Creator: CreatorResource{
+				ID:       game.Creator.ID,
+				Username: game.Creator.Username,
+			},
+			CreatedAt: game.CreatedAt,
+		})
+	}
+
+	return LobbyResponse{
+		Data: data,
+		Meta: LobbyMeta{NextCursor: nextCursor},
+	}
+}
@@ -0,0 +1,85 @@
+package responses
+
+import (
+	"app/http/inputs"
+	"app/models"
+	"time"
+)
+
+type MatchResource struct {
+	Code            string             `json:"code"`
+	Result          string             `json:"result"`
+	Score           uint               `json:"score"`
+	Opponents       []OpponentResource `json:"opponents"`
+	Bet             uint               `json:"bet"`
+	Payout          uint               `json:"payout"`
+	Currency        CurrencyResource   `json:"currency"`
+	WinningPoints   uint               `json:"winning_points"`
+	DurationSeconds int64              `json:"duration_seconds"`
+	StartedAt       time.Time          `json:"started_at"`
+	FinishedAt      time.Time          `json:"finished_at"`
+}
+
+// OpponentResource is another player of a finished game, without the seat
+// and table details that only matter while a game is being played.
+type OpponentResource struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Score    uint   `json:"score"`
+	IsWinner bool   `json:"is_winner"`
+}
+
+type MatchesResponse struct {
+	Data []MatchResource `json:"data"`
+	Meta PaginationMeta  `json:"meta"`
+}
+
+// NewMatchResource describes a finished game from the point of view of userId.
+func NewMatchResource(userId uint, game models.Game) MatchResource {
+	resource := MatchResource{
+		Code:            game.Code,
+		Result:          inputs.ResultLoss,
+		Opponents:       make([]OpponentResource, 0, len(game.Participants)),
+		Bet:             game.Bet,
+		Currency:        NewCurrencyResource(game.Currency),
+		WinningPoints:   game.WinningPoints,
+		DurationSeconds: int64(game.Duration().Seconds()),
+		StartedAt:       game.StartedAt,
+		FinishedAt:      game.FinishedAt,
+	}
+
+	for _, p := range game.Participants {
+		if p.UserID != userId {
+			resource.Opponents = append(resource.Opponents, OpponentResource{
+				ID:       p.UserID,
+				Username: p.User.Username,
+				Score:    p.Score,
+				IsWinner: p.IsWinner,
+			})
+
+			continue
+		}
+
+		resource.Score = p.Score
+		resource.Payout = p.Payout
+
+		if p.IsWinner {
+			resource.Result = inputs.ResultWin
+		}
+	}
+
+	return resource
+}
+
+func NewMatchesResponse(userId uint, games []models.Game, page inputs.PaginationInput, total int64) MatchesResponse {
+	data := make([]MatchResource, 0, len(games))
+
+	for _, game := range games {
+		data = append(data, NewMatchResource(userId, game))
+	}
+
+	return MatchesResponse{
+		Data: data,
+		Meta: NewPaginationMeta(page, total),
+	}
+}
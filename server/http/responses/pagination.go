@@ -0,0 +1,25 @@
+package responses
+
+import "app/http/inputs"
+
+type PaginationMeta struct {
+	Page     int   `json:"page"`
+	PerPage  int   `json:"per_page"`
+	Total    int64 `json:"total"`
+	LastPage int64 `json:"last_page"`
+}
+
+func NewPaginationMeta(page inputs.PaginationInput, total int64) PaginationMeta {
+	lastPage := (total + int64(page.PerPage) - 1) / int64(page.PerPage)
+
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	return PaginationMeta{
+		Page:     page.Page,
+		PerPage:  page.PerPage,
+		Total:    total,
+		LastPage: lastPage,
+	}
+}
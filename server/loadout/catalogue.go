@@ -0,0 +1,173 @@
+// Package loadout is the catalogue of special dice and badges players can
+// own and bring to a game.
+package loadout
+
+import (
+	"app/fairness"
+	"app/farkle"
+	"app/models"
+	"fmt"
+)
+
+// StandardDie is the ordinary die every player has an unlimited supply of.
+const StandardDie = "standard"
+
+type Die struct {
+	Slug        string
+	Name        string
+	Description string
+	Price       uint
+	Currency    string
+	Die         fairness.Die
+}
+
+// Chances is the probability of each face, in the order of Die.Faces.
+func (die Die) Chances() [6]float64 {
+	var total uint
+	var chances [6]float64
+
+	for _, w := range die.Die.Weights {
+		total += w
+	}
+
+	for i, w := range die.Die.Weights {
+		chances[i] = float64(w) / float64(total)
+	}
+
+	return chances
+}
+
+type Badge struct {
+	Slug        farkle.Badge
+	Name        string
+	Description string
+	Price       uint
+	Currency    string
+}
+
+// Dice lists every die in the order they are shown.
+var Dice = []Die{
+	{
+		Slug:        StandardDie,
+		Name:        "Ordinary die",
+		Description: "A plain, honest die",
+		Die:         fairness.Standard,
+	},
+	{
+		Slug:        "lucky",
+		Name:        "Lucky die",
+		Description: "Shows a one more often than it should",
+		Price:       300,
+		Currency:    models.BRONZE,
+		Die: fairness.Die{
+			Faces:   [6]int{1, 2, 3, 4, 5, 6},
+			Weights: [6]uint{4, 2, 2, 2, 3, 2},
+		},
+	},
+	{
+		Slug:        "devil",
+		Name:        "Devil's head die",
+		Description: "The devil's head replaces the one and counts as any face",
+		Price:       600,
+		Currency:    models.BRONZE,
+		Die: fairness.Die{
+			Faces:   [6]int{farkle.Wildcard, 2, 3, 4, 5, 6},
+			Weights: [6]uint{1, 1, 1, 1, 1, 1},
+		},
+	},
+	{
+		Slug:        "odd",
+		Name:        "Odd die",
+		Description: "Favours odd faces",
+		Price:       150,
+		Currency:    models.BRONZE,
+		Die: fairness.Die{
+			Faces:   [6]int{1, 2, 3, 4, 5, 6},
+			Weights: [6]uint{4, 1, 4, 1, 4, 1},
+		},
+	},
+	{
+		Slug:        "even",
+		Name:        "Even die",
+		Description: "Favours even faces",
+		Price:       150,
+		Currency:    models.BRONZE,
+		Die: fairness.Die{
+			Faces:   [6]int{1, 2, 3, 4, 5, 6},
+			Weights: [6]uint{1, 4, 1, 4, 1, 4},
+		},
+	},
+}
+
+// init refuses a catalogue with a die that cannot be thrown, which would
+// otherwise hang every roll made with it.
+func init() {
+	for _, die := range Dice {
+		if err := die.Die.Validate(); err != nil {
+			panic(fmt.Sprintf("loadout: die %s: %v", die.Slug, err))
+		}
+	}
+}
+
+// Badges lists every badge in the order they are shown.
+var Badges = []Badge{
+	{
+		Slug:        farkle.BadgeScoreBonus,
+		Name:        "Badge of Fortune",
+		Description: "Adds 10% to every banked turn",
+		Price:       800,
+		Currency:    models.BRONZE,
+	},
+	{
+		Slug:        farkle.BadgeExtraThrow,
+		Name:        "Badge of Second Chances",
+		Description: "Your first farkle of the game is thrown again",
+		Price:       500,
+		Currency:    models.BRONZE,
+	},
+	{
+		Slug:        farkle.BadgeReroll,
+		Name:        "Badge of the Nimble Hand",
+		Description: "Throw one die on the table again, once per game",
+		Price:       400,
+		Currency:    models.BRONZE,
+	},
+}
+
+func FindDie(slug string) (Die, bool) {
+	for _, die := range Dice {
+		if die.Slug == slug {
+			return die, true
+		}
+	}
+
+	return Die{}, false
+}
+
+func FindBadge(slug string) (Badge, bool) {
+	for _, badge := range Badges {
+		if string(badge.Slug) == slug {
+			return badge, true
+		}
+	}
+
+	return Badge{}, false
+}
+
+// Resolve turns a loadout of die slugs into the dice to roll. Unknown
+// slugs fall back to the standard die.
+func Resolve(slugs []string) []fairness.Die {
+	dice := make([]fairness.Die, len(slugs))
+
+	for i, slug := range slugs {
+		die, ok := FindDie(slug)
+
+		if !ok {
+			die, _ = FindDie(StandardDie)
+		}
+
+		dice[i] = die.Die
+	}
+
+	return dice
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"app/config"
+	"app/container"
 	"app/routes"
 	"log"
+	"os"
 
 	"app/database"
 
@@ -12,18 +14,55 @@ import (
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(cfg.Args) > 0 {
+		if err := runCommand(cfg, cfg.Args); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	app := fiber.New(fiber.Config{
 		CaseSensitive: true,
 		ServerHeader:  "Fiber",
 		AppName:       "Tavern Dice",
 	})
 	app.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173"},
+		AllowOrigins:     cfg.CORSOrigins,
 		AllowCredentials: false,
 	}))
 
-	database.Connect()
-	routes.SetupRoutes(app)
+	db, err := database.Connect(cfg)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := database.EnsureMigrated(db); err != nil {
+		log.Fatal(err)
+	}
+
+	c := container.New(cfg, db)
+
+	if err := c.Timers.Start(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := c.Bots.Start(); err != nil {
+		log.Fatal(err)
+	}
+
+	routes.SetupRoutes(app, c)
 	
-	log.Fatal(app.Listen(":" + config.Config("API_PORT")))
+	log.Fatal(app.Listen(":" + cfg.APIPort))
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserAchievement is a user's progress towards a catalogue achievement.
+type UserAchievement struct {
+	UserID     uint      `json:"user_id" gorm:"primaryKey; not null"`
+	Slug       string    `json:"slug" gorm:"primaryKey; type:varchar(64); not null"`
+	Progress   uint      `json:"progress" gorm:"not null; default:0"`
+	UnlockedAt time.Time `json:"unlocked_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	User       User      `json:"-" gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE"`
+}
+
+func (achievement UserAchievement) IsUnlocked() bool {
+	return !achievement.UnlockedAt.IsZero()
+}
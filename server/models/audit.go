@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Audit events.
+const (
+	AuditLoginLocked    = "login_locked"
+	AuditLockoutCleared = "lockout_cleared"
+)
+
+// AuditLog records a security relevant event. UserID is empty when the
+// event is not tied to a known account, such as a lockout of an IP.
+type AuditLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Event     string    `json:"event" gorm:"index; not null; type:varchar(64)"`
+	UserID    *uint     `json:"user_id" gorm:"index"`
+	Username  string    `json:"username" gorm:"type:varchar(255)"`
+	IP        string    `json:"ip" gorm:"index; type:varchar(64)"`
+	Detail    string    `json:"detail" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	FriendRequestPending  = "pending"
+	FriendRequestAccepted = "accepted"
+	FriendRequestDeclined = "declined"
+)
+
+type FriendRequest struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	SenderID    uint      `json:"sender_id" gorm:"index; not null"`
+	ReceiverID  uint      `json:"receiver_id" gorm:"index; not null"`
+	Status      string    `json:"status" gorm:"type:varchar(255); default:'pending'; not null; index"`
+	RespondedAt time.Time `json:"responded_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Sender      User      `json:"sender" gorm:"foreignKey:SenderID; constraint:OnDelete:CASCADE"`
+	Receiver    User      `json:"receiver" gorm:"foreignKey:ReceiverID; constraint:OnDelete:CASCADE"`
+}
@@ -2,29 +2,210 @@ package models
 
 import "time"
 
+const (
+	DefaultMinPlayers = 2
+	DefaultMaxPlayers = 2
+)
+
 type Game struct {
-	ID            uint      `json:"id" gorm:"primaryKey"`
-	Code          string    `json:"code" gorm:"uniqueIndex; not null; type:varchar(255)"`
-	CurrencyID    uint      `json:"currency_id" gorm:"index; not null"`
-	CreatorID     uint      `json:"creator_id" gorm:"index; not null"`
-	Bet           uint      `json:"bet" gorm:"not null"`
-	WinningPoints uint      `json:"winning_points" gorm:"not null"`
-	JoinType      string    `json:"join_type" gorm:"type:varchar(255); default:'anyone'; not null; index"`
-	StartedAt     time.Time `json:"started_at"`
-	FinishedAt    time.Time `json:"finished_at"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	Currency      Currency  `json:"currency"`
-	Creator       User      `json:"creator"`
-	Users         []User    `json:"users" gorm:"many2many:game_user;"`
+	ID            uint   `json:"id" gorm:"primaryKey"`
+	Code          string `json:"code" gorm:"uniqueIndex; not null; type:varchar(255)"`
+	CurrencyID    uint   `json:"currency_id" gorm:"index; not null"`
+	CreatorID     uint   `json:"creator_id" gorm:"index; not null"`
+	Bet           uint   `json:"bet" gorm:"not null"`
+	WinningPoints uint   `json:"winning_points" gorm:"not null"`
+	JoinType      string `json:"join_type" gorm:"type:varchar(255); default:'anyone'; not null; index"`
+	MinPlayers    uint   `json:"min_players" gorm:"not null; default:2"`
+	MaxPlayers    uint   `json:"max_players" gorm:"not null; default:2"`
+	// Seating is how the turn order is set: drawn at random when the game
+	// starts, or arranged by the creator in the lobby.
+	Seating string `json:"seating" gorm:"type:varchar(255); default:'random'; not null"`
+	// NoSpectators closes the game to spectators; it is open by default.
+	NoSpectators bool `json:"no_spectators" gorm:"not null; default:false"`
+	// Rules are the house rules the game is played by.
+	Rules GameRules `json:"rules" gorm:"serializer:json"`
+	// TurnSeconds limits every turn and GameSeconds is each player's time
+	// bank for the whole game. Zero means no limit.
+	TurnSeconds    uint       `json:"turn_seconds" gorm:"not null; default:0"`
+	GameSeconds    uint       `json:"game_seconds" gorm:"not null; default:0"`
+	ServerSeed     string     `json:"-" gorm:"type:varchar(64)"`
+	ServerSeedHash string     `json:"server_seed_hash" gorm:"type:varchar(64)"`
+	StartedAt      time.Time  `json:"started_at"`
+	FinishedAt     time.Time  `json:"finished_at"`
+	CancelledAt    time.Time  `json:"cancelled_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Currency       Currency   `json:"currency"`
+	Creator        User       `json:"creator"`
+	Users          []User     `json:"users" gorm:"many2many:game_user;"`
+	Participants   []GameUser `json:"participants" gorm:"foreignKey:GameID"`
+	State          *GameState `json:"state,omitempty" gorm:"foreignKey:GameID; constraint:OnDelete:CASCADE"`
+	PlayersCount   uint       `json:"players_count" gorm:"->; -:migration"`
+}
+
+// GameRules mirror farkle.Rules field for field so either converts to the other.
+type GameRules struct {
+	OpeningScore     uint   `json:"opening_score"`
+	FarklePenalty    uint   `json:"farkle_penalty"`
+	OfAKind          string `json:"of_a_kind"`
+	PartialStraights bool   `json:"partial_straights"`
+	CatchUp          bool   `json:"catch_up"`
+}
+
+// AllowsSpectators reports whether users outside the game may watch it.
+func (game Game) AllowsSpectators() bool {
+	return !game.NoSpectators
+}
+
+func (game Game) IsStarted() bool {
+	return !game.StartedAt.IsZero()
+}
+
+func (game Game) IsFinished() bool {
+	return !game.FinishedAt.IsZero()
+}
+
+func (game Game) IsCancelled() bool {
+	return !game.CancelledAt.IsZero()
+}
+
+// Duration is how long the game was played for.
+func (game Game) Duration() time.Duration {
+	if !game.IsStarted() || !game.IsFinished() {
+		return 0
+	}
+
+	return game.FinishedAt.Sub(game.StartedAt)
+}
+
+// IsTimed reports whether the game runs turn clocks.
+func (game Game) IsTimed() bool {
+	return game.TurnSeconds > 0 || game.GameSeconds > 0
+}
+
+// TimeLeft is what remains of the player's time bank before the current
+// turn; it is only meaningful when the game has one.
+func (game Game) TimeLeft(player GameUser) time.Duration {
+	budget := time.Duration(game.GameSeconds) * time.Second
+
+	if player.TimeUsed >= budget {
+		return 0
+	}
+
+	return budget - player.TimeUsed
+}
+
+// TurnDeadline is when the current turn runs out for the player taking it:
+// the turn limit or the end of their time bank, whichever comes first. It
+// is zero when the turn is not timed.
+func (game Game) TurnDeadline(player GameUser) time.Time {
+	if !game.IsTimed() || game.IsFinished() || game.State == nil || game.State.TurnStartedAt.IsZero() {
+		return time.Time{}
+	}
+
+	var deadline time.Time
+
+	if game.TurnSeconds > 0 {
+		deadline = game.State.TurnStartedAt.Add(time.Duration(game.TurnSeconds) * time.Second)
+	}
+
+	if game.GameSeconds > 0 {
+		bank := game.State.TurnStartedAt.Add(game.TimeLeft(player))
+
+		if deadline.IsZero() || bank.Before(deadline) {
+			deadline = bank
+		}
+	}
+
+	return deadline
+}
+
+func (game Game) Capacity() uint {
+	if game.MaxPlayers == 0 {
+		return DefaultMaxPlayers
+	}
+
+	return game.MaxPlayers
+}
+
+// Quorum is how many players must be seated before the game can start.
+func (game Game) Quorum() uint {
+	if game.MinPlayers == 0 {
+		return DefaultMinPlayers
+	}
+
+	return game.MinPlayers
 }
 
 type GameUser struct {
-	UserID   uint `json:"user_id" gorm:"primaryKey; index; not null"`
-	GameID   uint `json:"game_id" gorm:"primaryKey; index; not null"`
-	IsWinner bool `json:"is_winner" gorm:"index; not null; default:false; type:boolean"`
+	UserID     uint   `json:"user_id" gorm:"primaryKey; index; not null"`
+	GameID     uint   `json:"game_id" gorm:"primaryKey; index; not null"`
+	IsWinner   bool   `json:"is_winner" gorm:"index; not null; default:false; type:boolean"`
+	Score      uint   `json:"score" gorm:"not null; default:0"`
+	BestTurn   uint   `json:"best_turn" gorm:"not null; default:0"`
+	ClientSeed string `json:"client_seed" gorm:"type:varchar(64)"`
+	// Loadout is the die slug in each of the player's six slots; empty means standard dice.
+	Loadout   []string `json:"loadout" gorm:"serializer:json"`
+	Badge     string   `json:"badge" gorm:"type:varchar(32)"`
+	BadgeUsed bool     `json:"badge_used" gorm:"not null; default:false"`
+	Stake     uint     `json:"stake" gorm:"not null; default:0"`
+	Payout    uint     `json:"payout" gorm:"not null; default:0"`
+	// Seat is the player's place in the turn order, counted from one.
+	Seat uint `json:"seat" gorm:"not null; default:0"`
+	// Farkles counts the player's farkles in a row.
+	Farkles uint `json:"farkles" gorm:"not null; default:0"`
+	// ReadyAt is set while the player is ready for the game to start.
+	ReadyAt time.Time `json:"ready_at"`
+	// ForfeitedAt is set when the player gave up a started game. Their stake
+	// stays in the pot and they take no further turns.
+	ForfeitedAt time.Time `json:"forfeited_at"`
+	// TimeUsed is how long the player's finished turns took.
+	TimeUsed  time.Duration `json:"time_used" gorm:"not null; default:0"`
+	CreatedAt time.Time     `json:"created_at"`
+	User      User          `json:"user" gorm:"foreignKey:UserID"`
 }
 
 func (GameUser) TableName() string {
 	return "game_user"
 }
+
+func (player GameUser) IsReady() bool {
+	return !player.ReadyAt.IsZero()
+}
+
+func (player GameUser) HasForfeited() bool {
+	return !player.ForfeitedAt.IsZero()
+}
+
+// GameState is the persisted turn of a started game.
+type GameState struct {
+	GameID        uint   `json:"game_id" gorm:"primaryKey"`
+	CurrentUserID uint   `json:"current_user_id" gorm:"index; not null"`
+	Phase         string `json:"phase" gorm:"type:varchar(255); not null"`
+	Dice          []int  `json:"dice" gorm:"serializer:json"`
+	Slots         []int  `json:"slots" gorm:"serializer:json"`
+	DiceLeft      uint   `json:"dice_left" gorm:"not null"`
+	TurnScore     uint   `json:"turn_score" gorm:"not null; default:0"`
+	Turn          uint   `json:"turn" gorm:"not null; default:1"`
+	Nonce         uint   `json:"nonce" gorm:"not null; default:0"`
+	// LastRoundBy is the player who started the last round under the
+	// catch-up rule; LastTurns are the players still owed their last turn.
+	LastRoundBy uint   `json:"last_round_by" gorm:"not null; default:0"`
+	LastTurns   []uint `json:"last_turns" gorm:"serializer:json"`
+	// TurnStartedAt is when the current player's clock started.
+	TurnStartedAt time.Time `json:"turn_started_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// GameRoll is the audit record of a single provably fair roll.
+type GameRoll struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	GameID     uint      `json:"game_id" gorm:"uniqueIndex:idx_game_rolls_nonce; not null"`
+	Nonce      uint      `json:"nonce" gorm:"uniqueIndex:idx_game_rolls_nonce; not null"`
+	UserID     uint      `json:"user_id" gorm:"index; not null"`
+	ClientSeed string    `json:"client_seed" gorm:"type:varchar(64); not null"`
+	Slots      []int     `json:"slots" gorm:"serializer:json"`
+	Dice       []int     `json:"dice" gorm:"serializer:json"`
+	CreatedAt  time.Time `json:"created_at"`
+}
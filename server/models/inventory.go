@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Inventory item kinds.
+const (
+	ItemDie   = "die"
+	ItemBadge = "badge"
+)
+
+// InventoryItem is how many of a catalogue die or badge a user owns.
+type InventoryItem struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey; not null"`
+	Kind      string    `json:"kind" gorm:"primaryKey; type:varchar(16); not null"`
+	Slug      string    `json:"slug" gorm:"primaryKey; type:varchar(64); not null"`
+	Quantity  uint      `json:"quantity" gorm:"not null; default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	User      User      `json:"-" gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE"`
+}
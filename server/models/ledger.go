@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Ledger reasons.
+const (
+	LedgerRegistrationGrant = "registration_grant"
+	LedgerBetEscrow         = "bet_escrow"
+	LedgerPayout            = "payout"
+	LedgerRefund            = "refund"
+	LedgerAdminAdjustment   = "admin_adjustment"
+	LedgerPurchase          = "purchase"
+)
+
+// Ledger accounts. Funds move between a user's balance and either the
+// escrow of a game or the system, which mints grants and adjustments.
+const (
+	AccountUser   = "user"
+	AccountEscrow = "escrow"
+	AccountSystem = "system"
+)
+
+// LedgerEntry is one leg of an append-only double-entry transfer. Each
+// transfer writes a user leg and a counter leg whose deltas sum to zero.
+// BalanceAfter is the user's resulting balance on the user leg and the
+// game pot on an escrow leg.
+type LedgerEntry struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	TransferID   string    `json:"transfer_id" gorm:"index; not null; type:varchar(255)"`
+	Account      string    `json:"account" gorm:"index; not null; type:varchar(255)"`
+	UserID       uint      `json:"user_id" gorm:"index; not null"`
+	CurrencyID   uint      `json:"currency_id" gorm:"index; not null"`
+	GameID       *uint     `json:"game_id" gorm:"index"`
+	Delta        int64     `json:"delta" gorm:"not null"`
+	Reason       string    `json:"reason" gorm:"index; not null; type:varchar(255)"`
+	BalanceAfter uint      `json:"balance_after" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"`
+	Currency     Currency  `json:"currency"`
+	Game         *Game     `json:"game,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Session is a signed-in device. Its refresh token is stored only as a
+// hash and replaced on every refresh; all sessions rotated from the same
+// sign-in share a FamilyID.
+type Session struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	UserID           uint      `json:"user_id" gorm:"index; not null"`
+	FamilyID         string    `json:"family_id" gorm:"index; not null; type:varchar(36)"`
+	RefreshTokenHash string    `json:"-" gorm:"uniqueIndex; not null; type:varchar(64)"`
+	UserAgent        string    `json:"user_agent" gorm:"type:varchar(255)"`
+	IP               string    `json:"ip" gorm:"type:varchar(64)"`
+	ExpiresAt        time.Time `json:"expires_at" gorm:"not null"`
+	RevokedAt        time.Time `json:"revoked_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	User             User      `json:"-" gorm:"foreignKey:UserID; constraint:OnDelete:CASCADE"`
+}
+
+func (session Session) IsRevoked() bool {
+	return !session.RevokedAt.IsZero()
+}
+
+func (session Session) IsActive() bool {
+	return !session.IsRevoked() && time.Now().Before(session.ExpiresAt)
+}
@@ -3,12 +3,22 @@ package models
 import "time"
 
 type User struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Username  string    `json:"username" gorm:"uniqueIndex;not null;type:varchar(255)"`
-	Password  string    `json:"password" gorm:"not null"`
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	Username string `json:"username" gorm:"uniqueIndex;not null;type:varchar(255)"`
+	Password string `json:"password" gorm:"not null"`
+	// IsBot users are computer opponents. They have no password and cannot log in.
+	IsBot       bool   `json:"is_bot" gorm:"not null; default:false"`
+	BotStrategy string `json:"bot_strategy,omitempty" gorm:"type:varchar(32)"`
+	// DeletedAt is set when the account was closed. The row is kept, anonymised,
+	// so opponents' match history stays intact.
+	DeletedAt time.Time `json:"deleted_at"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Balances  []Balance
 	Friends   []*User `gorm:"many2many:user_friends"`
 	Games     []Game  `gorm:"many2many:game_user"`
 }
+
+func (user User) IsDeleted() bool {
+	return !user.DeletedAt.IsZero()
+}
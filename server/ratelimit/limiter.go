@@ -0,0 +1,153 @@
+// Package ratelimit throttles repeated attempts per key with exponential
+// backoff and a temporary lockout.
+package ratelimit
+
+import (
+	"strings"
+	"time"
+)
+
+// Policy describes how a limiter reacts to attempts on a key. The first
+// FreeAttempts go through untouched, every further one doubles the wait
+// starting at BaseDelay up to MaxDelay, and reaching MaxAttempts locks the
+// key out for Lockout. Attempts are forgotten after Window of quiet.
+type Policy struct {
+	FreeAttempts int
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Lockout      time.Duration
+	Window       time.Duration
+}
+
+// Result is the state of a key after an attempt.
+type Result struct {
+	// Allowed is false when the key was still waiting; such an attempt is
+	// not counted.
+	Allowed  bool
+	Attempts int
+	// RetryAfter is how long the key must wait before its next attempt.
+	RetryAfter time.Duration
+	// Locked is true when this attempt started a lockout.
+	Locked bool
+}
+
+type Limiter struct {
+	store  Store
+	policy Policy
+	now    func() time.Time
+}
+
+func NewLimiter(store Store, policy Policy) *Limiter {
+	return NewLimiterWithClock(store, policy, time.Now)
+}
+
+// NewLimiterWithClock creates a limiter that tells the time with now.
+func NewLimiterWithClock(store Store, policy Policy, now func() time.Time) *Limiter {
+	return &Limiter{store: store, policy: policy, now: now}
+}
+
+// Key joins parts into a case-insensitive store key.
+func Key(parts ...string) string {
+	return strings.ToLower(strings.Join(parts, ":"))
+}
+
+// Attempt lets an attempt on the key go ahead unless the key is waiting
+// out a backoff or lockout. An attempt that goes ahead is counted and
+// blocks the key for the backoff delay, or locks it out once MaxAttempts
+// is reached. Checking and counting are a single store update, so a burst
+// of concurrent attempts cannot all pass before the first is counted.
+func (limiter *Limiter) Attempt(key string) Result {
+	now := limiter.now()
+	var result Result
+
+	limiter.store.Update(key, func(entry Entry, found bool) (Entry, time.Duration) {
+		if wait := entry.BlockedUntil.Sub(now); found && wait > 0 {
+			result = Result{Attempts: entry.Attempts, RetryAfter: wait}
+
+			return entry, limiter.ttl(entry, now)
+		}
+
+		if !found || entry.Locked {
+			entry = Entry{}
+		}
+
+		entry.Attempts++
+		entry.LastAttempt = now
+		result = Result{Allowed: true, Attempts: entry.Attempts}
+
+		switch {
+		case entry.Attempts >= limiter.policy.MaxAttempts:
+			entry.Locked = true
+			entry.BlockedUntil = now.Add(limiter.policy.Lockout)
+			result.Locked = true
+		case entry.Attempts > limiter.policy.FreeAttempts:
+			entry.BlockedUntil = now.Add(limiter.backoff(entry.Attempts - limiter.policy.FreeAttempts))
+		}
+
+		if wait := entry.BlockedUntil.Sub(now); wait > 0 {
+			result.RetryAfter = wait
+		}
+
+		return entry, limiter.ttl(entry, now)
+	})
+
+	return result
+}
+
+// Refund takes back the last counted attempt of the key, for attempts that
+// only count when they fail. The backoff it caused is lifted; a lockout is
+// not.
+func (limiter *Limiter) Refund(key string) {
+	now := limiter.now()
+
+	limiter.store.Update(key, func(entry Entry, found bool) (Entry, time.Duration) {
+		if !found || entry.Locked || entry.Attempts == 0 {
+			return entry, limiter.ttl(entry, now)
+		}
+
+		entry.Attempts--
+		entry.BlockedUntil = time.Time{}
+
+		if step := entry.Attempts - limiter.policy.FreeAttempts; step > 0 {
+			entry.BlockedUntil = entry.LastAttempt.Add(limiter.backoff(step))
+		}
+
+		return entry, limiter.ttl(entry, now)
+	})
+}
+
+// Reset forgets every attempt of the key, reporting whether its last
+// attempt had locked it out.
+func (limiter *Limiter) Reset(key string) bool {
+	entry, ok := limiter.store.Delete(key)
+
+	return ok && entry.Locked
+}
+
+// ttl keeps an entry for a window of quiet after its last counted attempt
+// or until its block ends, whichever is later. An entry without attempts
+// is not kept.
+func (limiter *Limiter) ttl(entry Entry, now time.Time) time.Duration {
+	if entry.Attempts == 0 {
+		return 0
+	}
+
+	expiresAt := entry.LastAttempt.Add(limiter.policy.Window)
+
+	if entry.BlockedUntil.After(expiresAt) {
+		expiresAt = entry.BlockedUntil
+	}
+
+	return expiresAt.Sub(now)
+}
+
+func (limiter *Limiter) backoff(step int) time.Duration {
+	delay := limiter.policy.BaseDelay
+
+	for i := 1; i < step && delay < limiter.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, limiter.policy.MaxDelay)
+}
@@ -0,0 +1,226 @@
+package ratelimit_test
+
+import (
+	"app/ratelimit"
+	"sync"
+	"testing"
+	"time"
+)
+
+var policy = ratelimit.Policy{
+	FreeAttempts: 2,
+	MaxAttempts:  6,
+	BaseDelay:    time.Second,
+	MaxDelay:     3 * time.Second,
+	Lockout:      time.Minute,
+	Window:       10 * time.Minute,
+}
+
+// clock is a time that only moves when a test says so.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newLimiter() (*ratelimit.Limiter, *clock) {
+	c := &clock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := ratelimit.NewMemoryStoreWithClock(c.Now)
+
+	return ratelimit.NewLimiterWithClock(store, policy, c.Now), c
+}
+
+func TestKey(t *testing.T) {
+	if got := ratelimit.Key("login:user", "Alice"); got != "login:user:alice" {
+		t.Fatalf("Key = %q", got)
+	}
+}
+
+func TestAttemptBacksOffThenLocks(t *testing.T) {
+	limiter, c := newLimiter()
+
+	steps := []struct {
+		attempts   int
+		retryAfter time.Duration
+		locked     bool
+	}{
+		{1, 0, false},
+		{2, 0, false},
+		{3, time.Second, false},
+		{4, 2 * time.Second, false},
+		{5, 3 * time.Second, false},
+		{6, time.Minute, true},
+	}
+
+	for _, step := range steps {
+		result := limiter.Attempt("key")
+		want := ratelimit.Result{Allowed: true, Attempts: step.attempts, RetryAfter: step.retryAfter, Locked: step.locked}
+
+		if result != want {
+			t.Fatalf("attempt %d = %+v, want %+v", step.attempts, result, want)
+		}
+
+		c.Advance(step.retryAfter)
+	}
+}
+
+func TestAttemptRefusedWhileBlocked(t *testing.T) {
+	limiter, c := newLimiter()
+
+	for range policy.FreeAttempts + 1 {
+		limiter.Attempt("key")
+	}
+
+	c.Advance(400 * time.Millisecond)
+	result := limiter.Attempt("key")
+	want := ratelimit.Result{Attempts: policy.FreeAttempts + 1, RetryAfter: 600 * time.Millisecond}
+
+	if result != want {
+		t.Fatalf("blocked attempt = %+v, want %+v", result, want)
+	}
+
+	c.Advance(600 * time.Millisecond)
+
+	if result := limiter.Attempt("key"); !result.Allowed || result.Attempts != policy.FreeAttempts+2 {
+		t.Fatalf("attempt after the backoff = %+v", result)
+	}
+}
+
+func TestLockoutEndsWithAFreshCount(t *testing.T) {
+	limiter, c := newLimiter()
+
+	for range policy.MaxAttempts {
+		limiter.Attempt("key")
+		c.Advance(policy.MaxDelay)
+	}
+
+	if result := limiter.Attempt("key"); result.Allowed {
+		t.Fatalf("attempt during lockout = %+v", result)
+	}
+
+	c.Advance(policy.Lockout)
+
+	if result := limiter.Attempt("key"); !result.Allowed || result.Attempts != 1 {
+		t.Fatalf("attempt after lockout = %+v", result)
+	}
+}
+
+func TestWindowForgetsAttempts(t *testing.T) {
+	limiter, c := newLimiter()
+
+	limiter.Attempt("key")
+	limiter.Attempt("key")
+	c.Advance(policy.Window + time.Second)
+
+	if result := limiter.Attempt("key"); result.Attempts != 1 {
+		t.Fatalf("attempt after the window = %+v", result)
+	}
+}
+
+func TestKeysAreIndependent(t *testing.T) {
+	limiter, _ := newLimiter()
+
+	for range policy.MaxAttempts {
+		limiter.Attempt("a")
+	}
+
+	if result := limiter.Attempt("b"); !result.Allowed || result.Attempts != 1 {
+		t.Fatalf("other key = %+v", result)
+	}
+}
+
+func TestReset(t *testing.T) {
+	limiter, c := newLimiter()
+
+	limiter.Attempt("key")
+
+	if limiter.Reset("key") {
+		t.Fatal("Reset reported a lockout that never happened")
+	}
+
+	for range policy.MaxAttempts {
+		limiter.Attempt("key")
+		c.Advance(policy.MaxDelay)
+	}
+
+	if !limiter.Reset("key") {
+		t.Fatal("Reset did not report the lockout")
+	}
+
+	if result := limiter.Attempt("key"); !result.Allowed || result.Attempts != 1 {
+		t.Fatalf("attempt after Reset = %+v", result)
+	}
+}
+
+func TestRefund(t *testing.T) {
+	limiter, _ := newLimiter()
+
+	for range policy.FreeAttempts + 1 {
+		limiter.Attempt("key")
+	}
+
+	limiter.Refund("key")
+
+	if result := limiter.Attempt("key"); !result.Allowed || result.Attempts != policy.FreeAttempts+1 {
+		t.Fatalf("attempt after a refund = %+v", result)
+	}
+
+	limiter.Refund("unknown")
+
+	if result := limiter.Attempt("unknown"); result.Attempts != 1 {
+		t.Fatalf("refund of an unknown key = %+v", result)
+	}
+}
+
+func TestRefundKeepsLockout(t *testing.T) {
+	limiter, c := newLimiter()
+
+	for range policy.MaxAttempts {
+		limiter.Attempt("key")
+		c.Advance(policy.MaxDelay)
+	}
+
+	limiter.Refund("key")
+
+	if result := limiter.Attempt("key"); result.Allowed {
+		t.Fatalf("attempt during a refunded lockout = %+v", result)
+	}
+}
+
+func TestConcurrentAttempts(t *testing.T) {
+	limiter, _ := newLimiter()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+
+	for range 100 {
+		wg.Go(func() {
+			if limiter.Attempt("key").Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	// the free attempts and the one that starts the backoff
+	if allowed != policy.FreeAttempts+1 {
+		t.Fatalf("%d concurrent attempts went ahead, want %d", allowed, policy.FreeAttempts+1)
+	}
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry is what the limiter remembers about a key.
+type Entry struct {
+	Attempts     int
+	LastAttempt  time.Time
+	BlockedUntil time.Time
+	// Locked is set while the key sits out a lockout rather than a backoff.
+	Locked bool
+}
+
+// Store keeps entries between attempts. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Update replaces the entry of the key with what update makes of it,
+	// atomically with respect to every other call on the same key. found
+	// is false when there was no live entry. The entry expires after the
+	// returned ttl; a ttl of zero or less drops it.
+	Update(key string, update func(entry Entry, found bool) (Entry, time.Duration))
+	// Delete drops the entry of the key, returning it when it was live.
+	Delete(key string) (Entry, bool)
+}
+
+type memoryItem struct {
+	entry     Entry
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store held in process memory. Expired entries are
+// dropped lazily and on a periodic sweep.
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+	sweep time.Time
+	now   func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithClock(time.Now)
+}
+
+// NewMemoryStoreWithClock creates a store that tells the time with now.
+func NewMemoryStoreWithClock(now func() time.Time) *MemoryStore {
+	return &MemoryStore{items: make(map[string]memoryItem), now: now}
+}
+
+func (store *MemoryStore) Update(key string, update func(entry Entry, found bool) (Entry, time.Duration)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.now()
+	item, found := store.items[key]
+
+	if found && now.After(item.expiresAt) {
+		item, found = memoryItem{}, false
+	}
+
+	entry, ttl := update(item.entry, found)
+
+	if ttl <= 0 {
+		delete(store.items, key)
+	} else {
+		store.items[key] = memoryItem{entry: entry, expiresAt: now.Add(ttl)}
+	}
+
+	if now.Sub(store.sweep) < time.Minute {
+		return
+	}
+
+	store.sweep = now
+
+	for k, item := range store.items {
+		if now.After(item.expiresAt) {
+			delete(store.items, k)
+		}
+	}
+}
+
+func (store *MemoryStore) Delete(key string) (Entry, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	item, ok := store.items[key]
+	delete(store.items, key)
+
+	if !ok || store.now().After(item.expiresAt) {
+		return Entry{}, false
+	}
+
+	return item.entry, true
+}
@@ -0,0 +1,61 @@
+package ratelimit_test
+
+import (
+	"app/ratelimit"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreUpdate(t *testing.T) {
+	c := &clock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := ratelimit.NewMemoryStoreWithClock(c.Now)
+
+	store.Update("key", func(entry ratelimit.Entry, found bool) (ratelimit.Entry, time.Duration) {
+		if found {
+			t.Fatal("found an entry in an empty store")
+		}
+
+		entry.Attempts = 1
+
+		return entry, time.Minute
+	})
+
+	store.Update("key", func(entry ratelimit.Entry, found bool) (ratelimit.Entry, time.Duration) {
+		if !found || entry.Attempts != 1 {
+			t.Fatalf("entry = %+v, found %v", entry, found)
+		}
+
+		return entry, time.Minute
+	})
+
+	c.Advance(time.Minute + time.Second)
+
+	store.Update("key", func(entry ratelimit.Entry, found bool) (ratelimit.Entry, time.Duration) {
+		if found {
+			t.Fatalf("expired entry was found: %+v", entry)
+		}
+
+		return entry, 0
+	})
+
+	if _, ok := store.Delete("key"); ok {
+		t.Fatal("an entry with no ttl was kept")
+	}
+}
+
+func TestMemoryStoreDelete(t *testing.T) {
+	c := &clock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := ratelimit.NewMemoryStoreWithClock(c.Now)
+
+	store.Update("key", func(entry ratelimit.Entry, found bool) (ratelimit.Entry, time.Duration) {
+		return ratelimit.Entry{Attempts: 3, Locked: true}, time.Minute
+	})
+
+	if entry, ok := store.Delete("key"); !ok || entry.Attempts != 3 || !entry.Locked {
+		t.Fatalf("Delete = %+v, %v", entry, ok)
+	}
+
+	if _, ok := store.Delete("key"); ok {
+		t.Fatal("Delete found the entry twice")
+	}
+}
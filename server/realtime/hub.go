@@ -0,0 +1,300 @@
+// Package realtime fans game events out to WebSocket clients, one room per game.
+package realtime
+
+import (
+	"app/events"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+	sendBuffer = 32
+)
+
+// Message is the envelope of everything written to a socket.
+type Message struct {
+	Type events.Type `json:"type"`
+	Data any         `json:"data"`
+}
+
+const Snapshot events.Type = "snapshot"
+
+type client struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	room      string
+	userId    uint
+	spectator bool
+	// evicted is closed to make the writer flush what is queued and hang up
+	evicted chan struct{}
+	evict   sync.Once
+}
+
+type Hub struct {
+	bus   *events.Bus
+	mu    sync.RWMutex
+	rooms map[string]map[*client]struct{}
+}
+
+// NewHub creates a hub that relays every event published on the bus to
+// the sockets connected to the event's game. It publishes when a player's
+// first socket in a game connects and when their last one goes away, and
+// whenever a spectator comes or goes.
+func NewHub(bus *events.Bus) *Hub {
+	hub := &Hub{bus: bus, rooms: make(map[string]map[*client]struct{})}
+	bus.Subscribe(hub.broadcast)
+
+	return hub
+}
+
+// Serve registers a player's connection in the game's room, sends it the
+// full state snapshot and blocks until the client goes away.
+func (hub *Hub) Serve(conn *websocket.Conn, gameCode string, userId uint, snapshot any) {
+	hub.serve(&client{
+		conn:    conn,
+		send:    make(chan []byte, sendBuffer),
+		room:    gameCode,
+		userId:  userId,
+		evicted: make(chan struct{}),
+	}, snapshot)
+}
+
+// Watch is Serve for a spectator: the connection gets the same events but
+// does not count towards the user's presence at the table.
+func (hub *Hub) Watch(conn *websocket.Conn, gameCode string, userId uint, snapshot any) {
+	hub.serve(&client{
+		conn:      conn,
+		send:      make(chan []byte, sendBuffer),
+		room:      gameCode,
+		userId:    userId,
+		spectator: true,
+		evicted:   make(chan struct{}),
+	}, snapshot)
+}
+
+// Spectators is how many spectator sockets are watching the game.
+func (hub *Hub) Spectators(gameCode string) uint {
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	return hub.spectators(gameCode)
+}
+
+func (hub *Hub) serve(c *client, snapshot any) {
+	payload, err := json.Marshal(Message{Type: Snapshot, Data: snapshot})
+
+	if err != nil {
+		log.Println("realtime: failed to encode snapshot:", err)
+		c.conn.Close()
+
+		return
+	}
+
+	c.send <- payload
+
+	if first, watching := hub.register(c); c.spectator {
+		hub.bus.Publish(spectatorEvent(events.SpectatorJoined, c, watching))
+	} else if first {
+		hub.bus.Publish(events.Event{Type: events.PlayerConnected, GameCode: c.room, UserID: c.userId})
+	}
+
+	// the connection is released as soon as Serve returns, so wait for the writer
+	written := make(chan struct{})
+
+	go func() {
+		c.writePump()
+		close(written)
+	}()
+
+	c.readPump()
+
+	if last, watching, ok := hub.unregister(c); ok && c.spectator {
+		hub.bus.Publish(spectatorEvent(events.SpectatorLeft, c, watching))
+	} else if last {
+		hub.bus.Publish(events.Event{Type: events.PlayerDisconnected, GameCode: c.room, UserID: c.userId})
+	}
+
+	<-written
+}
+
+// register adds the client to its room, reporting whether it is the
+// player's first socket there and how many spectators the room has.
+func (hub *Hub) register(c *client) (bool, uint) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if hub.rooms[c.room] == nil {
+		hub.rooms[c.room] = make(map[*client]struct{})
+	}
+
+	first := !c.spectator && !hub.present(c.room, c.userId)
+	hub.rooms[c.room][c] = struct{}{}
+
+	return first, hub.spectators(c.room)
+}
+
+// unregister removes the client from its room, reporting whether it was
+// the player's last socket there, how many spectators are left and whether
+// the client was registered at all.
+func (hub *Hub) unregister(c *client) (bool, uint, bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	room := hub.rooms[c.room]
+
+	if _, ok := room[c]; !ok {
+		return false, 0, false
+	}
+
+	delete(room, c)
+	close(c.send)
+
+	if len(room) == 0 {
+		delete(hub.rooms, c.room)
+	}
+
+	return !c.spectator && !hub.present(c.room, c.userId), hub.spectators(c.room), true
+}
+
+// present reports whether the player has a socket in the room. The caller
+// holds the lock.
+func (hub *Hub) present(room string, userId uint) bool {
+	for c := range hub.rooms[room] {
+		if !c.spectator && c.userId == userId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// spectators counts the spectator sockets in the room. The caller holds
+// the lock.
+func (hub *Hub) spectators(room string) uint {
+	var count uint
+
+	for c := range hub.rooms[room] {
+		if c.spectator {
+			count++
+		}
+	}
+
+	return count
+}
+
+// spectatorEvent tells the room how many spectators it has now.
+func spectatorEvent(eventType events.Type, c *client, watching uint) events.Event {
+	return events.Event{
+		Type:     eventType,
+		GameCode: c.room,
+		UserID:   c.userId,
+		Data:     map[string]uint{"spectators": watching},
+	}
+}
+
+func (hub *Hub) broadcast(event events.Event) {
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+		log.Println("realtime: failed to encode event:", err)
+		return
+	}
+
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	for c := range hub.rooms[event.GameCode] {
+		select {
+		case c.send <- payload:
+		default:
+			// the client is not keeping up, drop it so it reconnects for a fresh snapshot
+			c.conn.Close()
+		}
+
+		// spectators hear that the creator closed the game to them, then the
+		// writer closes their socket
+		if c.spectator && event.Type == events.SpectatingClosed {
+			c.evict.Do(func() { close(c.evicted) })
+		}
+	}
+}
+
+// readPump only watches the connection: clients act through the REST API.
+func (c *client) readPump() {
+	c.conn.SetReadLimit(512)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-c.evicted:
+			c.flush()
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+
+			return
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// flush writes whatever is still queued for the client without waiting
+// for more.
+func (c *client) flush() {
+	for {
+		select {
+		case payload, ok := <-c.send:
+			if !ok {
+				return
+			}
+
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"app/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AchievementRepository struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+func (repo *AchievementRepository) FindByUser(userId uint) ([]models.UserAchievement, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.UserAchievement](repo.db).
+		Where("user_id = ?", userId).
+		Find(ctx)
+}
+
+func (repo *AchievementRepository) Save(achievement *models.UserAchievement) error {
+	return repo.db.Save(achievement).Error
+}
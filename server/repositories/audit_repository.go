@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"app/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (repo *AuditRepository) Create(log *models.AuditLog) error {
+	ctx := context.Background()
+
+	return gorm.G[models.AuditLog](repo.db).Create(ctx, log)
+}
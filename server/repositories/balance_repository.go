@@ -3,14 +3,27 @@ package repositories
 import (
 	"app/models"
 	"context"
+	"errors"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
 type BalanceRepository struct {
 	db *gorm.DB
 }
 
+// Movement describes a single change of a user's balance.
+type Movement struct {
+	UserID     uint
+	CurrencyID uint
+	Amount     uint
+	Reason     string
+	GameID     *uint
+}
+
 func NewBalanceRepository(db *gorm.DB) *BalanceRepository {
 	return &BalanceRepository{db: db}
 }
@@ -36,3 +49,127 @@ func (repo *BalanceRepository) FindByUserAndCurrency(user models.User, currencyI
 
 	return &balance, nil
 }
+
+// Debit takes the amount from a balance and records it in the ledger. The
+// balance is checked and updated in a single statement, so two debits can
+// never spend the same funds.
+func (repo *BalanceRepository) Debit(movement Movement) error {
+	ctx := context.Background()
+	rows, err := gorm.G[models.Balance](repo.db).
+		Where("user_id = ? AND currency_id = ? AND amount >= ?", movement.UserID, movement.CurrencyID, movement.Amount).
+		Update(ctx, "amount", gorm.Expr("amount - ?", movement.Amount))
+
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrInsufficientBalance
+	}
+
+	return repo.record(movement, -int64(movement.Amount))
+}
+
+// Credit adds the amount to a balance, opening one in the currency if
+// needed, and records it in the ledger.
+func (repo *BalanceRepository) Credit(movement Movement) error {
+	ctx := context.Background()
+	rows, err := gorm.G[models.Balance](repo.db).
+		Where("user_id = ? AND currency_id = ?", movement.UserID, movement.CurrencyID).
+		Update(ctx, "amount", gorm.Expr("amount + ?", movement.Amount))
+
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		err = gorm.G[models.Balance](repo.db).Create(ctx, &models.Balance{
+			UserID:     movement.UserID,
+			CurrencyID: movement.CurrencyID,
+			Amount:     movement.Amount,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return repo.record(movement, int64(movement.Amount))
+}
+
+// record writes both legs of a transfer: the user leg with the resulting
+// balance and the counter leg on the game's escrow account for stakes,
+// payouts and refunds, or on the system account for everything else. Other
+// movements may still name a game, like the house funding a bot's stake.
+func (repo *BalanceRepository) record(movement Movement, delta int64) error {
+	ctx := context.Background()
+	balance, err := gorm.G[models.Balance](repo.db).
+		Where("user_id = ? AND currency_id = ?", movement.UserID, movement.CurrencyID).
+		First(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	counter := models.AccountSystem
+	var pot uint
+
+	if movement.GameID != nil && movesEscrow(movement.Reason) {
+		counter = models.AccountEscrow
+
+		if pot, err = repo.escrowed(*movement.GameID); err != nil {
+			return err
+		}
+
+		pot = uint(int64(pot) - delta)
+	}
+
+	transferId := uuid.New().String()
+	entries := []models.LedgerEntry{
+		{
+			TransferID:   transferId,
+			Account:      models.AccountUser,
+			UserID:       movement.UserID,
+			CurrencyID:   movement.CurrencyID,
+			GameID:       movement.GameID,
+			Delta:        delta,
+			Reason:       movement.Reason,
+			BalanceAfter: balance.Amount,
+		},
+		{
+			TransferID:   transferId,
+			Account:      counter,
+			UserID:       movement.UserID,
+			CurrencyID:   movement.CurrencyID,
+			GameID:       movement.GameID,
+			Delta:        -delta,
+			Reason:       movement.Reason,
+			BalanceAfter: pot,
+		},
+	}
+
+	return gorm.G[models.LedgerEntry](repo.db).CreateInBatches(ctx, &entries, len(entries))
+}
+
+// movesEscrow reports whether a movement with the reason pays into or out
+// of a game's escrow.
+func movesEscrow(reason string) bool {
+	switch reason {
+	case models.LedgerBetEscrow, models.LedgerPayout, models.LedgerRefund:
+		return true
+	default:
+		return false
+	}
+}
+
+// escrowed is the amount currently held in a game's escrow account.
+func (repo *BalanceRepository) escrowed(gameId uint) (uint, error) {
+	var total int64
+
+	err := repo.db.Model(&models.LedgerEntry{}).
+		Where("account = ? AND game_id = ?", models.AccountEscrow, gameId).
+		Select("COALESCE(SUM(delta), 0)").
+		Scan(&total).Error
+
+	return uint(total), err
+}
@@ -1,7 +1,6 @@
 package repositories
 
 import (
-	"app/database"
 	"app/models"
 	"context"
 
@@ -19,7 +18,21 @@ func NewCurrencyRepository(db *gorm.DB) *CurrencyRepository {
 func (repo *CurrencyRepository) FindById(currencyId uint) (models.Currency, error) {
 	ctx := context.Background()
 
-	return gorm.G[models.Currency](database.DB).
+	return gorm.G[models.Currency](repo.db).
 		Where("id = ?", currencyId).
 		First(ctx)
 }
+
+func (repo *CurrencyRepository) FindBySlug(slug string) (models.Currency, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.Currency](repo.db).
+		Where("slug = ?", slug).
+		First(ctx)
+}
+
+func (repo *CurrencyRepository) FindAll() ([]models.Currency, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.Currency](repo.db).Find(ctx)
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"app/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type FriendRepository struct {
+	db *gorm.DB
+}
+
+func NewFriendRepository(db *gorm.DB) *FriendRepository {
+	return &FriendRepository{db: db}
+}
+
+func (repo *FriendRepository) CreateRequest(request *models.FriendRequest) error {
+	ctx := context.Background()
+
+	return gorm.G[models.FriendRequest](repo.db).Create(ctx, request)
+}
+
+func (repo *FriendRepository) FindRequest(requestId uint) (*models.FriendRequest, error) {
+	ctx := context.Background()
+	request, err := gorm.G[models.FriendRequest](repo.db).
+		Where("id = ?", requestId).
+		Preload("Sender", nil).
+		Preload("Receiver", nil).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// FindPendingBetween returns the pending request sent from senderId to receiverId.
+func (repo *FriendRepository) FindPendingBetween(senderId, receiverId uint) (*models.FriendRequest, error) {
+	ctx := context.Background()
+	request, err := gorm.G[models.FriendRequest](repo.db).
+		Where("sender_id = ? AND receiver_id = ? AND status = ?", senderId, receiverId, models.FriendRequestPending).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// FindPending returns the user's pending requests, both received and sent.
+func (repo *FriendRepository) FindPending(userId uint) ([]models.FriendRequest, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.FriendRequest](repo.db).
+		Where("status = ?", models.FriendRequestPending).
+		Where("sender_id = ? OR receiver_id = ?", userId, userId).
+		Preload("Sender", nil).
+		Preload("Receiver", nil).
+		Order("created_at DESC").
+		Find(ctx)
+}
+
+func (repo *FriendRepository) UpdateStatus(request *models.FriendRequest) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.FriendRequest](repo.db).
+		Where("id = ?", request.ID).
+		Updates(ctx, models.FriendRequest{
+			Status:      request.Status,
+			RespondedAt: request.RespondedAt,
+		})
+
+	return err
+}
+
+func (repo *FriendRepository) DeleteRequest(requestId uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.FriendRequest](repo.db).
+		Where("id = ?", requestId).
+		Delete(ctx)
+
+	return err
+}
+
+// AddFriendship links both users to each other.
+func (repo *FriendRepository) AddFriendship(userId, friendId uint) error {
+	return repo.db.Exec(
+		"INSERT INTO user_friends (user_id, friend_id) VALUES (?, ?), (?, ?) ON CONFLICT DO NOTHING",
+		userId, friendId, friendId, userId,
+	).Error
+}
+
+// RemoveFriendship unlinks both users, reporting whether they were friends.
+func (repo *FriendRepository) RemoveFriendship(userId, friendId uint) (bool, error) {
+	result := repo.db.Exec(
+		"DELETE FROM user_friends WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)",
+		userId, friendId, friendId, userId,
+	)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (repo *FriendRepository) FindFriends(userId uint) ([]models.User, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.User](repo.db).
+		Where("id IN (SELECT friend_id FROM user_friends WHERE user_id = ?)", userId).
+		Order("username").
+		Find(ctx)
+}
+
+// RemoveAll drops every friendship and friend request of the user.
+func (repo *FriendRepository) RemoveAll(userId uint) error {
+	err := repo.db.Exec(
+		"DELETE FROM user_friends WHERE user_id = ? OR friend_id = ?",
+		userId, userId,
+	).Error
+
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	_, err = gorm.G[models.FriendRequest](repo.db).
+		Where("sender_id = ? OR receiver_id = ?", userId, userId).
+		Delete(ctx)
+
+	return err
+}
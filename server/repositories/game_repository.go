@@ -1,10 +1,10 @@
 package repositories
 
 import (
-	"app/database"
 	"app/http/inputs"
 	"app/models"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -27,9 +27,425 @@ func (repo *GameRepository) CreateGame(user models.User, input inputs.CreateGame
 		Bet:           input.Bet,
 		WinningPoints: input.WinningPoints,
 		JoinType:      input.JoinType,
+		MinPlayers:    input.MinPlayers,
+		MaxPlayers:    input.MaxPlayers,
+		Seating:       input.Seating,
+		Rules:         models.GameRules(input.Rules.Rules()),
 	}
 
-	err := gorm.G[models.Game](database.DB).Create(ctx, &game)
+	if input.AllowSpectators != nil {
+		game.NoSpectators = !*input.AllowSpectators
+	}
+
+	if input.TurnSeconds != nil {
+		game.TurnSeconds = *input.TurnSeconds
+	}
+
+	if input.GameSeconds != nil {
+		game.GameSeconds = *input.GameSeconds
+	}
+
+	err := gorm.G[models.Game](repo.db).Create(ctx, &game)
 
 	return &game, err
 }
+
+func (repo *GameRepository) FindByCode(code string) (*models.Game, error) {
+	ctx := context.Background()
+	game, err := gorm.G[models.Game](repo.db).
+		Where("code = ?", code).
+		Preload("Currency", nil).
+		Preload("State", nil).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
+
+// FindPlayers returns the players of a game in turn order.
+func (repo *GameRepository) FindPlayers(gameId uint) ([]models.GameUser, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.GameUser](repo.db).
+		Where("game_id = ?", gameId).
+		Preload("User", nil).
+		Order("seat, created_at, user_id").
+		Find(ctx)
+}
+
+// AddPlayer seats a user after everyone already in the game with the stake
+// already taken from their balance.
+func (repo *GameRepository) AddPlayer(gameId, userId, stake uint) error {
+	ctx := context.Background()
+	var last uint
+
+	err := repo.db.Table("game_user").
+		Where("game_id = ?", gameId).
+		Select("COALESCE(MAX(seat), 0)").
+		Scan(&last).Error
+
+	if err != nil {
+		return err
+	}
+
+	return gorm.G[models.GameUser](repo.db).Create(ctx, &models.GameUser{
+		GameID: gameId,
+		UserID: userId,
+		Seat:   last + 1,
+		Stake:  stake,
+	})
+}
+
+// UpdateReady marks the player ready at the given time, or not ready when it is zero.
+func (repo *GameRepository) UpdateReady(gameId, userId uint, at time.Time) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "ready_at", at)
+
+	return err
+}
+
+// UpdateSeats seats the players in the given order of user ids.
+func (repo *GameRepository) UpdateSeats(gameId uint, order []uint) error {
+	ctx := context.Background()
+
+	for i, userId := range order {
+		_, err := gorm.G[models.GameUser](repo.db).
+			Where("game_id = ? AND user_id = ?", gameId, userId).
+			Update(ctx, "seat", i+1)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemovePlayer frees a user's seat in a game that has not started.
+func (repo *GameRepository) RemovePlayer(gameId, userId uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Delete(ctx)
+
+	return err
+}
+
+func (repo *GameRepository) UpdateStake(gameId, userId, stake uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "stake", stake)
+
+	return err
+}
+
+func (repo *GameRepository) MarkForfeited(gameId, userId uint, at time.Time) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "forfeited_at", at)
+
+	return err
+}
+
+func (repo *GameRepository) UpdatePayout(gameId, userId, payout uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "payout", payout)
+
+	return err
+}
+
+func (repo *GameRepository) UpdateClientSeed(gameId, userId uint, clientSeed string) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "client_seed", clientSeed)
+
+	return err
+}
+
+func (repo *GameRepository) CreateRoll(roll *models.GameRoll) error {
+	ctx := context.Background()
+
+	return gorm.G[models.GameRoll](repo.db).Create(ctx, roll)
+}
+
+func (repo *GameRepository) FindRolls(gameId uint) ([]models.GameRoll, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.GameRoll](repo.db).
+		Where("game_id = ?", gameId).
+		Order("nonce").
+		Find(ctx)
+}
+
+func (repo *GameRepository) SaveState(state *models.GameState) error {
+	return repo.db.Save(state).Error
+}
+
+func (repo *GameRepository) UpdateScore(gameId, userId, score uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "score", score)
+
+	return err
+}
+
+func (repo *GameRepository) UpdateFarkles(gameId, userId, farkles uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "farkles", farkles)
+
+	return err
+}
+
+func (repo *GameRepository) UpdateBestTurn(gameId, userId, bestTurn uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "best_turn", bestTurn)
+
+	return err
+}
+
+func (repo *GameRepository) UpdateLoadout(gameId, userId uint, loadout []string, badge string) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Select("Loadout", "Badge").
+		Updates(ctx, models.GameUser{Loadout: loadout, Badge: badge})
+
+	return err
+}
+
+func (repo *GameRepository) MarkBadgeUsed(gameId, userId uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "badge_used", true)
+
+	return err
+}
+
+func (repo *GameRepository) UpdateTimeUsed(gameId, userId uint, timeUsed time.Duration) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", gameId, userId).
+		Update(ctx, "time_used", timeUsed)
+
+	return err
+}
+
+// MarkStarted stamps the game as started and stores the committed server seed.
+func (repo *GameRepository) MarkStarted(game *models.Game) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.Game](repo.db).
+		Where("id = ?", game.ID).
+		Updates(ctx, models.Game{
+			StartedAt:      game.StartedAt,
+			ServerSeed:     game.ServerSeed,
+			ServerSeedHash: game.ServerSeedHash,
+		})
+
+	return err
+}
+
+// MarkFinished stamps the game as finished and flags the winner.
+func (repo *GameRepository) MarkFinished(game *models.Game, winnerId uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.Game](repo.db).
+		Where("id = ?", game.ID).
+		Update(ctx, "finished_at", game.FinishedAt)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = gorm.G[models.GameUser](repo.db).
+		Where("game_id = ? AND user_id = ?", game.ID, winnerId).
+		Update(ctx, "is_winner", true)
+
+	return err
+}
+
+func (repo *GameRepository) MarkCancelled(game *models.Game) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.Game](repo.db).
+		Where("id = ?", game.ID).
+		Update(ctx, "cancelled_at", game.CancelledAt)
+
+	return err
+}
+
+func (repo *GameRepository) UpdateSpectating(game *models.Game) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.Game](repo.db).
+		Where("id = ?", game.ID).
+		Update(ctx, "no_spectators", game.NoSpectators)
+
+	return err
+}
+
+// LobbyFilter selects open public games. Results are ordered by Column and
+// then id, and AfterID/AfterValue continue from the last game of the
+// previous page.
+type LobbyFilter struct {
+	CurrencyID uint
+	MinBet     uint
+	MaxBet     uint
+	Column     string
+	Desc       bool
+	AfterID    uint
+	AfterValue uint
+	Limit      int
+}
+
+const playersCountSql = "(SELECT COUNT(*) FROM game_user WHERE game_user.game_id = games.id)"
+
+// FindOpenGames returns public games that have not started and still have free seats.
+func (repo *GameRepository) FindOpenGames(joinType string, filter LobbyFilter) ([]models.Game, error) {
+	ctx := context.Background()
+	query := gorm.G[models.Game](repo.db).
+		Select("games.*, "+playersCountSql+" AS players_count").
+		Where("join_type = ?", joinType).
+		Where("started_at IS NULL OR started_at = ?", time.Time{}).
+		Where("cancelled_at IS NULL OR cancelled_at = ?", time.Time{}).
+		Where(playersCountSql + " < max_players")
+
+	if filter.CurrencyID > 0 {
+		query = query.Where("currency_id = ?", filter.CurrencyID)
+	}
+
+	if filter.MinBet > 0 {
+		query = query.Where("bet >= ?", filter.MinBet)
+	}
+
+	if filter.MaxBet > 0 {
+		query = query.Where("bet <= ?", filter.MaxBet)
+	}
+
+	direction, compare := "ASC", ">"
+
+	if filter.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	if filter.AfterID > 0 {
+		if filter.Column == "id" {
+			query = query.Where("id "+compare+" ?", filter.AfterID)
+		} else {
+			query = query.Where(
+				"("+filter.Column+" "+compare+" ?) OR ("+filter.Column+" = ? AND id "+compare+" ?)",
+				filter.AfterValue,
+				filter.AfterValue,
+				filter.AfterID,
+			)
+		}
+	}
+
+	if filter.Column != "id" {
+		query = query.Order(filter.Column + " " + direction)
+	}
+
+	return query.
+		Order("id "+direction).
+		Preload("Currency", nil).
+		Preload("Creator", nil).
+		Limit(filter.Limit).
+		Find(ctx)
+}
+
+// FindFinishedForUser returns a page of the finished games a user played,
+// most recent first. won narrows the result to wins or losses when set.
+func (repo *GameRepository) FindFinishedForUser(userId uint, won *bool, offset, limit int) ([]models.Game, int64, error) {
+	ctx := context.Background()
+	participation := repo.db.Table("game_user").Select("game_id").Where("user_id = ?", userId)
+
+	if won != nil {
+		participation = participation.Where("is_winner = ?", *won)
+	}
+
+	query := gorm.G[models.Game](repo.db).
+		Where("id IN (?)", participation).
+		Where("finished_at > ?", time.Time{})
+
+	total, err := query.Count(ctx, "id")
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	games, err := query.
+		Preload("Currency", nil).
+		Preload("Participants", func(db gorm.PreloadBuilder) error {
+			db.Order("created_at, user_id")
+
+			return nil
+		}).
+		Preload("Participants.User", nil).
+		Order("finished_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(ctx)
+
+	return games, total, err
+}
+
+// FindTimedInPlay returns the timed games that have started and are not
+// over yet.
+func (repo *GameRepository) FindTimedInPlay() ([]models.Game, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.Game](repo.db).
+		Where("turn_seconds > 0 OR game_seconds > 0").
+		Where("started_at IS NOT NULL AND started_at <> ?", time.Time{}).
+		Where("finished_at IS NULL OR finished_at = ?", time.Time{}).
+		Where("cancelled_at IS NULL OR cancelled_at = ?", time.Time{}).
+		Order("id").
+		Find(ctx)
+}
+
+// FindOnBotTurn returns the games in play whose current player is a bot,
+// with their state.
+func (repo *GameRepository) FindOnBotTurn() ([]models.Game, error) {
+	ctx := context.Background()
+	bots := repo.db.Table("users").Select("id").Where("is_bot = ?", true)
+	onTurn := repo.db.Table("game_states").Select("game_id").Where("current_user_id IN (?)", bots)
+
+	return gorm.G[models.Game](repo.db).
+		Where("id IN (?)", onTurn).
+		Where("started_at IS NOT NULL AND started_at <> ?", time.Time{}).
+		Where("finished_at IS NULL OR finished_at = ?", time.Time{}).
+		Where("cancelled_at IS NULL OR cancelled_at = ?", time.Time{}).
+		Preload("State", nil).
+		Order("id").
+		Find(ctx)
+}
+
+// FindActiveForUser returns the games a user is still playing, leaving out
+// those that have finished or been cancelled and those the user forfeited.
+func (repo *GameRepository) FindActiveForUser(userId uint) ([]models.Game, error) {
+	ctx := context.Background()
+	participation := repo.db.Table("game_user").Select("game_id").
+		Where("user_id = ?", userId).
+		Where("forfeited_at IS NULL OR forfeited_at = ?", time.Time{})
+
+	return gorm.G[models.Game](repo.db).
+		Where("id IN (?)", participation).
+		Where("finished_at IS NULL OR finished_at = ?", time.Time{}).
+		Where("cancelled_at IS NULL OR cancelled_at = ?", time.Time{}).
+		Preload("Currency", nil).
+		Preload("State", nil).
+		Order("id").
+		Find(ctx)
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"app/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+func (repo *InventoryRepository) FindByUser(userId uint) ([]models.InventoryItem, error) {
+	ctx := context.Background()
+
+	return gorm.G[models.InventoryItem](repo.db).
+		Where("user_id = ?", userId).
+		Order("kind, slug").
+		Find(ctx)
+}
+
+// Add increases the quantity of an item the user owns.
+func (repo *InventoryRepository) Add(userId uint, kind, slug string, quantity uint) error {
+	now := time.Now()
+	item := models.InventoryItem{
+		UserID:    userId,
+		Kind:      kind,
+		Slug:      slug,
+		Quantity:  quantity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return repo.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "kind"}, {Name: "slug"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"quantity":   gorm.Expr("quantity + ?", quantity),
+			"updated_at": now,
+		}),
+	}).Create(&item).Error
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LeaderboardRow is a user's aggregated result over the finished games of a window.
+type LeaderboardRow struct {
+	Rank     uint
+	UserID   uint
+	Username string
+	Games    uint
+	Wins     uint
+	Value    float64
+}
+
+// LeaderboardFilter selects the games a leaderboard is computed from.
+// Value is an SQL aggregate over game_user (gu) and games (g).
+type LeaderboardFilter struct {
+	Value      string
+	Since      time.Time
+	CurrencyID uint
+	MinGames   uint
+}
+
+type LeaderboardRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaderboardRepository(db *gorm.DB) *LeaderboardRepository {
+	return &LeaderboardRepository{db: db}
+}
+
+// FindTop returns the best ranked users. Users with equal values share a rank.
+func (repo *LeaderboardRepository) FindTop(filter LeaderboardFilter, limit int) ([]LeaderboardRow, error) {
+	ctx := context.Background()
+	sql, args := rankedSql(filter)
+	var rows []LeaderboardRow
+
+	err := repo.db.WithContext(ctx).
+		Raw(sql+" ORDER BY rank, user_id LIMIT ?", append(args, limit)...).
+		Scan(&rows).Error
+
+	return rows, err
+}
+
+// FindUser returns the rank of a single user, or nil when the user has no
+// qualifying games in the window.
+func (repo *LeaderboardRepository) FindUser(filter LeaderboardFilter, userId uint) (*LeaderboardRow, error) {
+	ctx := context.Background()
+	sql, args := rankedSql(filter)
+	var row LeaderboardRow
+
+	err := repo.db.WithContext(ctx).
+		Raw(sql+" WHERE user_id = ?", append(args, userId)...).
+		Take(&row).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func rankedSql(filter LeaderboardFilter) (string, []any) {
+	// bots play on house money and closed accounts are gone, so neither ranks
+	where := "g.finished_at > ? AND g.finished_at >= ? AND NOT u.is_bot AND (u.deleted_at IS NULL OR u.deleted_at <= ?)"
+	args := []any{time.Time{}, filter.Since, time.Time{}}
+
+	if filter.CurrencyID > 0 {
+		where += " AND g.currency_id = ?"
+		args = append(args, filter.CurrencyID)
+	}
+
+	args = append(args, filter.MinGames)
+
+	return `SELECT * FROM (
+		SELECT *, RANK() OVER (ORDER BY value DESC) AS rank FROM (
+			SELECT gu.user_id, u.username,
+				COUNT(*) AS games,
+				SUM(CASE WHEN gu.is_winner THEN 1 ELSE 0 END) AS wins,
+				` + filter.Value + ` AS value
+			FROM game_user gu
+			JOIN games g ON g.id = gu.game_id
+			JOIN users u ON u.id = gu.user_id
+			WHERE ` + where + `
+			GROUP BY gu.user_id, u.username
+			HAVING COUNT(*) >= ?
+		)
+	)`, args
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"app/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// FindUserEntries returns a page of the user's own ledger legs, newest first.
+func (repo *LedgerRepository) FindUserEntries(userId uint, offset, limit int) ([]models.LedgerEntry, int64, error) {
+	ctx := context.Background()
+	query := gorm.G[models.LedgerEntry](repo.db).
+		Where("account = ? AND user_id = ?", models.AccountUser, userId)
+
+	total, err := query.Count(ctx, "id")
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries, err := query.
+		Preload("Currency", nil).
+		Preload("Game", nil).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(ctx)
+
+	return entries, total, err
+}
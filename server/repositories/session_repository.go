@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"app/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrSessionRevoked = errors.New("session already revoked")
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (repo *SessionRepository) Create(session *models.Session) error {
+	ctx := context.Background()
+
+	return gorm.G[models.Session](repo.db).Create(ctx, session)
+}
+
+func (repo *SessionRepository) FindById(sessionId uint) (*models.Session, error) {
+	ctx := context.Background()
+	session, err := gorm.G[models.Session](repo.db).
+		Where("id = ?", sessionId).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (repo *SessionRepository) FindByRefreshTokenHash(hash string) (*models.Session, error) {
+	ctx := context.Background()
+	session, err := gorm.G[models.Session](repo.db).
+		Where("refresh_token_hash = ?", hash).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Revoke ends a single session. Sessions already revoked keep their time
+// and report ErrSessionRevoked, so only one caller can ever revoke it.
+func (repo *SessionRepository) Revoke(sessionId uint) error {
+	ctx := context.Background()
+	rows, err := gorm.G[models.Session](repo.db).
+		Where("id = ? AND (revoked_at IS NULL OR revoked_at = ?)", sessionId, time.Time{}).
+		Update(ctx, "revoked_at", time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
+
+// RevokeFamily ends every session rotated from the same sign-in.
+func (repo *SessionRepository) RevokeFamily(familyId string) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.Session](repo.db).
+		Where("family_id = ? AND (revoked_at IS NULL OR revoked_at = ?)", familyId, time.Time{}).
+		Update(ctx, "revoked_at", time.Now())
+
+	return err
+}
+
+// RevokeUser ends every session of the user, signing them out everywhere.
+func (repo *SessionRepository) RevokeUser(userId uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.Session](repo.db).
+		Where("user_id = ? AND (revoked_at IS NULL OR revoked_at = ?)", userId, time.Time{}).
+		Update(ctx, "revoked_at", time.Now())
+
+	return err
+}
+
+// RevokeOthers ends every session of the user except the given one.
+func (repo *SessionRepository) RevokeOthers(userId, sessionId uint) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.Session](repo.db).
+		Where("user_id = ? AND id <> ? AND (revoked_at IS NULL OR revoked_at = ?)", userId, sessionId, time.Time{}).
+		Update(ctx, "revoked_at", time.Now())
+
+	return err
+}
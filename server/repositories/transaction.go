@@ -0,0 +1,35 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// Tx groups the repositories bound to a single database transaction.
+type Tx struct {
+	Games     *GameRepository
+	Balances  *BalanceRepository
+	Users     *UserRepository
+	Friends   *FriendRepository
+	Inventory *InventoryRepository
+	Sessions  *SessionRepository
+}
+
+type Transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// Transaction runs fn in a database transaction, rolling back when it returns an error.
+func (transactor *Transactor) Transaction(fn func(tx *Tx) error) error {
+	return transactor.db.Transaction(func(db *gorm.DB) error {
+		return fn(&Tx{
+			Games:     NewGameRepository(db),
+			Balances:  NewBalanceRepository(db),
+			Users:     NewUserRepository(db),
+			Friends:   NewFriendRepository(db),
+			Inventory: NewInventoryRepository(db),
+			Sessions:  NewSessionRepository(db),
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"app/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// AreFriends reports whether friendId is in the friends list of userId.
+func (repo *UserRepository) AreFriends(userId, friendId uint) (bool, error) {
+	var exists bool
+
+	err := repo.db.Raw(
+		"SELECT EXISTS (SELECT 1 FROM user_friends WHERE user_id = ? AND friend_id = ?)",
+		userId,
+		friendId,
+	).Scan(&exists).Error
+
+	return exists, err
+}
+
+func (repo *UserRepository) Create(user *models.User) error {
+	ctx := context.Background()
+
+	return gorm.G[models.User](repo.db).Create(ctx, user)
+}
+
+// FindWithBalance returns the user with their balance in the given currency preloaded.
+func (repo *UserRepository) FindWithBalance(userId uint, currencySlug string) (*models.User, error) {
+	ctx := context.Background()
+	user, err := gorm.G[models.User](repo.db).
+		Where("id = ?", userId).
+		Preload("Balances", func(db gorm.PreloadBuilder) error {
+			subQuery := repo.db.Table("currencies").Select("id").Where("slug = ?", currencySlug)
+
+			db.Where("currency_id = (?)", subQuery)
+
+			return nil
+		}).
+		Preload("Balances.Currency", nil).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repo *UserRepository) FindByUsername(username string) (*models.User, error) {
+	ctx := context.Background()
+	user, err := gorm.G[models.User](repo.db).
+		Where("username = ?", username).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repo *UserRepository) FindById(userId uint) (*models.User, error) {
+	ctx := context.Background()
+	user, err := gorm.G[models.User](repo.db).
+		Where("id = ?", userId).
+		First(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// FindOrCreateBot returns the bot user playing the strategy, creating it on first use.
+func (repo *UserRepository) FindOrCreateBot(strategy, username string) (*models.User, error) {
+	user := models.User{Username: username, IsBot: true, BotStrategy: strategy}
+
+	err := repo.db.
+		Where(models.User{IsBot: true, BotStrategy: strategy}).
+		FirstOrCreate(&user).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repo *UserRepository) UpdatePassword(userId uint, password string) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.User](repo.db).
+		Where("id = ?", userId).
+		Update(ctx, "password", password)
+
+	return err
+}
+
+func (repo *UserRepository) UpdateUsername(userId uint, username string) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.User](repo.db).
+		Where("id = ?", userId).
+		Update(ctx, "username", username)
+
+	return err
+}
+
+// Anonymise replaces the user's name and password so the account can no
+// longer be found or signed into, and stamps it as deleted.
+func (repo *UserRepository) Anonymise(user *models.User) error {
+	ctx := context.Background()
+	_, err := gorm.G[models.User](repo.db).
+		Where("id = ?", user.ID).
+		Select("username", "password", "deleted_at").
+		Updates(ctx, models.User{
+			Username:  user.Username,
+			Password:  user.Password,
+			DeletedAt: user.DeletedAt,
+		})
+
+	return err
+}
@@ -1,37 +1,91 @@
 package routes
 
 import (
-	"app/database"
+	"app/container"
 	"app/http/handlers"
-	"app/http/middlewares"
-	"app/repositories"
-	"app/services"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/logger"
 )
 
-func SetupRoutes(app *fiber.App) {
+func SetupRoutes(app *fiber.App, c *container.Container) {
 	// Middleware
 	api := app.Group("/api", logger.New())
+	protected := c.Protected
+	h := c.Handlers
 
 	// Auth
-	api.Post("/login", handlers.Login)
-	api.Post("/register", handlers.Register)
+	api.Post("/login", h.Auth.Login)
+	api.Post("/register", h.Auth.Register)
+	api.Post("/token/refresh", h.Auth.Refresh)
+	api.Post("/logout", protected, h.Auth.Logout)
+	api.Post("/logout/all", protected, h.Auth.LogoutAll)
 
 	// Profile
-	api.Get("/profile", middlewares.Protected(), handlers.GetProfile)
+	api.Get("/profile", protected, handlers.GetProfile)
+	api.Get("/profile/transactions", protected, h.Ledger.GetTransactions)
+	api.Put("/profile/password", protected, h.Accounts.ChangePassword)
+	api.Put("/profile/username", protected, h.Accounts.Rename)
+	api.Delete("/profile", protected, h.Accounts.DeleteAccount)
+
+	// Friends
+	api.Get("/friends", protected, h.Friends.ListFriends)
+	api.Delete("/friends/:id", protected, h.Friends.RemoveFriend)
+	api.Get("/friends/requests", protected, h.Friends.ListRequests)
+	api.Post("/friends/requests", protected, h.Friends.SendRequest)
+	api.Post("/friends/requests/:id/accept", protected, h.Friends.AcceptRequest)
+	api.Post("/friends/requests/:id/decline", protected, h.Friends.DeclineRequest)
+	api.Delete("/friends/requests/:id", protected, h.Friends.CancelRequest)
 
 	// Games
-	balanceRepo := repositories.NewBalanceRepository(database.DB)
-	gameRepo := repositories.NewGameRepository(database.DB)
-	currencyRepo := repositories.NewCurrencyRepository(database.DB)
-	gameService := services.NewGameService(balanceRepo, currencyRepo, gameRepo)
-	gameHandler := handlers.NewGameHandler(gameService)
-	api.Post("/games", middlewares.Protected(), gameHandler.CreateGame)
+	api.Get("/games", protected, h.Lobby.ListGames)
+	api.Post("/games", protected, h.Games.CreateGame)
+	api.Delete("/games/:code", protected, h.Games.CancelGame)
+	api.Post("/games/:code/join", protected, h.Games.JoinGame)
+	api.Post("/games/:code/leave", protected, h.Games.LeaveGame)
+	api.Post("/games/:code/forfeit", protected, h.Games.ForfeitGame)
+	api.Post("/games/:code/start", protected, h.Games.StartGame)
+	api.Post("/games/:code/ready", protected, h.Games.Ready)
+	api.Delete("/games/:code/ready", protected, h.Games.Unready)
+	api.Put("/games/:code/seats", protected, h.Games.SetSeats)
+	api.Delete("/games/:code/players/:id", protected, h.Games.KickPlayer)
+	api.Post("/games/:code/spectators", protected, h.Games.OpenToSpectators)
+	api.Delete("/games/:code/spectators", protected, h.Games.CloseToSpectators)
+	api.Post("/games/:code/bots", protected, h.Games.AddBot)
+	api.Put("/games/:code/loadout", protected, h.Games.SetLoadout)
+	api.Post("/games/:code/score", protected, h.Games.ScoreSelection)
+
+	// Turns
+	api.Get("/games/:code/state", protected, h.Turns.GetState)
+	api.Post("/games/:code/roll", protected, h.Turns.Roll)
+	api.Post("/games/:code/set-aside", protected, h.Turns.SetAside)
+	api.Post("/games/:code/bank", protected, h.Turns.Bank)
+	api.Post("/games/:code/reroll", protected, h.Turns.Reroll)
+
+	// Realtime
+	api.Get("/games/:code/ws", c.ProtectedSocket, h.Socket.Connect)
+
+	// Provably fair rolls
+	api.Get("/games/:code/verify", h.Fairness.Verify)
+
+	// Match history
+	api.Get("/profile/matches", protected, h.Matches.GetProfileMatches)
+	api.Get("/users/:id/matches", protected, h.Matches.GetUserMatches)
+
+	// Leaderboard
+	api.Get("/leaderboard", protected, h.Leaderboard.GetLeaderboard)
+
+	// Achievements
+	api.Get("/profile/achievements", protected, h.Achievements.GetAchievements)
+
+	// Dice, badges and inventory
+	api.Get("/dice", h.Inventory.GetDice)
+	api.Get("/badges", h.Inventory.GetBadges)
+	api.Get("/profile/inventory", protected, h.Inventory.GetInventory)
+	api.Post("/profile/inventory", protected, h.Inventory.Purchase)
 
 	// Currencies
-	api.Get("/currencies", handlers.GetCurrencies)
+	api.Get("/currencies", h.Currencies.GetCurrencies)
 
 	// 404
 	app.Use(func(c fiber.Ctx) error {
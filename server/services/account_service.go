@@ -0,0 +1,201 @@
+package services
+
+import (
+	"app/events"
+	"app/http/inputs"
+	"app/models"
+	"app/repositories"
+	"app/utils"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWrongPassword    = errors.New("password is incorrect")
+	ErrUsernameTaken    = errors.New("username is already taken")
+	ErrUsernameReserved = errors.New("username is reserved")
+)
+
+// DeletedUsernamePrefix starts the name a closed account is renamed to.
+const DeletedUsernamePrefix = "deleted-"
+
+// RegistrationGrant is the bronze every new player starts with.
+const RegistrationGrant = 1000
+
+type AccountService struct {
+	currencyRepo *repositories.CurrencyRepository
+	userRepo     *repositories.UserRepository
+	transactor   *repositories.Transactor
+	bus          *events.Bus
+}
+
+func NewAccountService(
+	currencyRepo *repositories.CurrencyRepository,
+	userRepo *repositories.UserRepository,
+	transactor *repositories.Transactor,
+	bus *events.Bus,
+) *AccountService {
+	return &AccountService{
+		currencyRepo: currencyRepo,
+		userRepo:     userRepo,
+		transactor:   transactor,
+		bus:          bus,
+	}
+}
+
+func (service *AccountService) FindByUsername(username string) (*models.User, error) {
+	return service.userRepo.FindByUsername(username)
+}
+
+// Register creates the user and credits the registration grant.
+func (service *AccountService) Register(username, password string) (*models.User, error) {
+	if isReservedUsername(username) {
+		return nil, ErrUsernameReserved
+	}
+
+	if _, err := service.userRepo.FindByUsername(username); err == nil {
+		return nil, ErrUsernameTaken
+	}
+
+	currency, err := service.currencyRepo.FindBySlug(models.BRONZE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: username,
+		Password: utils.GeneratePassword(password),
+	}
+
+	err = service.transactor.Transaction(func(tx *repositories.Tx) error {
+		if err := tx.Users.Create(user); err != nil {
+			return err
+		}
+
+		return tx.Balances.Credit(repositories.Movement{
+			UserID:     user.ID,
+			CurrencyID: currency.ID,
+			Amount:     RegistrationGrant,
+			Reason:     models.LedgerRegistrationGrant,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ChangePassword replaces the password once the current one is confirmed and
+// signs the user out of every other session.
+func (service *AccountService) ChangePassword(authUser *models.User, sessionId uint, input inputs.ChangePasswordInput) error {
+	if !utils.IsValidPassword(authUser.Password, input.CurrentPassword) {
+		return ErrWrongPassword
+	}
+
+	return service.transactor.Transaction(func(tx *repositories.Tx) error {
+		if err := tx.Users.UpdatePassword(authUser.ID, utils.GeneratePassword(input.NewPassword)); err != nil {
+			return err
+		}
+
+		return tx.Sessions.RevokeOthers(authUser.ID, sessionId)
+	})
+}
+
+func (service *AccountService) Rename(authUser *models.User, input inputs.RenameInput) (*models.User, error) {
+	if input.Username == authUser.Username {
+		return authUser, nil
+	}
+
+	if isReservedUsername(input.Username) {
+		return nil, ErrUsernameReserved
+	}
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		if _, err := tx.Users.FindByUsername(input.Username); err == nil {
+			return ErrUsernameTaken
+		}
+
+		return tx.Users.UpdateUsername(authUser.ID, input.Username)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	authUser.Username = input.Username
+
+	return authUser, nil
+}
+
+// Delete closes the account. Lobbies the user sits in are left or called
+// off with stakes refunded, started games are forfeited, and the user row is
+// anonymised rather than removed so opponents keep their match history.
+func (service *AccountService) Delete(authUser *models.User, input inputs.DeleteAccountInput) error {
+	if !utils.IsValidPassword(authUser.Password, input.Password) {
+		return ErrWrongPassword
+	}
+
+	var published []events.Event
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		games, err := tx.Games.FindActiveForUser(authUser.ID)
+
+		if err != nil {
+			return err
+		}
+
+		for i := range games {
+			list, err := exitGame(tx, &games[i], authUser.ID)
+
+			if err != nil {
+				return err
+			}
+
+			published = append(published, list...)
+		}
+
+		if err := tx.Sessions.RevokeUser(authUser.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Friends.RemoveAll(authUser.ID); err != nil {
+			return err
+		}
+
+		return tx.Users.Anonymise(&models.User{
+			ID:        authUser.ID,
+			Username:  DeletedUsernamePrefix + uuid.NewString(),
+			DeletedAt: time.Now(),
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	service.bus.Publish(published...)
+
+	return nil
+}
+
+// isReservedUsername reports whether the name is kept for bots or closed
+// accounts, in any case.
+func isReservedUsername(username string) bool {
+	if strings.HasPrefix(strings.ToLower(username), DeletedUsernamePrefix) {
+		return true
+	}
+
+	for _, strategy := range inputs.Strategies {
+		if strings.EqualFold(username, botUsername(strategy)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"app/achievements"
+	"app/models"
+	"app/repositories"
+)
+
+// AchievementStatus is a catalogue achievement together with the user's progress.
+type AchievementStatus struct {
+	Definition  achievements.Definition
+	Achievement models.UserAchievement
+}
+
+type AchievementService struct {
+	achievementRepo *repositories.AchievementRepository
+}
+
+func NewAchievementService(achievementRepo *repositories.AchievementRepository) *AchievementService {
+	return &AchievementService{achievementRepo: achievementRepo}
+}
+
+// List returns every achievement in the catalogue, locked ones included.
+func (service *AchievementService) List(authUser *models.User) ([]AchievementStatus, error) {
+	unlocked, err := service.achievementRepo.FindByUser(authUser.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bySlug := make(map[string]models.UserAchievement, len(unlocked))
+
+	for _, a := range unlocked {
+		bySlug[a.Slug] = a
+	}
+
+	statuses := make([]AchievementStatus, 0, len(achievements.Catalogue))
+
+	for _, definition := range achievements.Catalogue {
+		statuses = append(statuses, AchievementStatus{
+			Definition:  definition,
+			Achievement: bySlug[definition.Slug],
+		})
+	}
+
+	return statuses, nil
+}
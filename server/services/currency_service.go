@@ -0,0 +1,18 @@
+package services
+
+import (
+	"app/models"
+	"app/repositories"
+)
+
+type CurrencyService struct {
+	currencyRepo *repositories.CurrencyRepository
+}
+
+func NewCurrencyService(currencyRepo *repositories.CurrencyRepository) *CurrencyService {
+	return &CurrencyService{currencyRepo: currencyRepo}
+}
+
+func (service *CurrencyService) List() ([]models.Currency, error) {
+	return service.currencyRepo.FindAll()
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"app/models"
+	"app/repositories"
+	"errors"
+)
+
+// escrowStake takes the game's bet from the user's balance and seats them
+// with it as their stake.
+func escrowStake(tx *repositories.Tx, game *models.Game, userId uint) error {
+	err := tx.Balances.Debit(repositories.Movement{
+		UserID:     userId,
+		CurrencyID: game.CurrencyID,
+		Amount:     game.Bet,
+		Reason:     models.LedgerBetEscrow,
+		GameID:     &game.ID,
+	})
+
+	if err != nil {
+		if errors.Is(err, repositories.ErrInsufficientBalance) {
+			return ErrInsufficientFunds
+		}
+
+		return err
+	}
+
+	return tx.Games.AddPlayer(game.ID, userId, game.Bet)
+}
+
+// fundBot tops a bot's balance up to the game's bet with a house adjustment.
+func fundBot(tx *repositories.Tx, game *models.Game, bot *models.User) error {
+	var amount uint
+
+	if balance, err := tx.Balances.FindByUserAndCurrency(*bot, game.CurrencyID); err == nil {
+		amount = balance.Amount
+	}
+
+	if amount >= game.Bet {
+		return nil
+	}
+
+	return tx.Balances.Credit(repositories.Movement{
+		UserID:     bot.ID,
+		CurrencyID: game.CurrencyID,
+		Amount:     game.Bet - amount,
+		Reason:     models.LedgerAdminAdjustment,
+		GameID:     &game.ID,
+	})
+}
+
+// payOut credits every escrowed stake to the winner.
+func payOut(tx *repositories.Tx, game *models.Game, players []models.GameUser, winnerId uint) error {
+	var pot uint
+
+	for _, p := range players {
+		pot += p.Stake
+	}
+
+	winner := findPlayer(players, winnerId)
+
+	if winner == nil || pot == 0 {
+		return nil
+	}
+
+	err := tx.Balances.Credit(repositories.Movement{
+		UserID:     winnerId,
+		CurrencyID: game.CurrencyID,
+		Amount:     pot,
+		Reason:     models.LedgerPayout,
+		GameID:     &game.ID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Games.UpdatePayout(game.ID, winnerId, pot); err != nil {
+		return err
+	}
+
+	winner.Payout = pot
+
+	return nil
+}
+
+// refundStakes returns every escrowed stake to its owner.
+func refundStakes(tx *repositories.Tx, game *models.Game, players []models.GameUser) error {
+	for i := range players {
+		if players[i].Stake == 0 {
+			continue
+		}
+
+		err := tx.Balances.Credit(repositories.Movement{
+			UserID:     players[i].UserID,
+			CurrencyID: game.CurrencyID,
+			Amount:     players[i].Stake,
+			Reason:     models.LedgerRefund,
+			GameID:     &game.ID,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Games.UpdateStake(game.ID, players[i].UserID, 0); err != nil {
+			return err
+		}
+
+		players[i].Stake = 0
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"app/fairness"
+	"app/loadout"
+	"app/models"
+	"app/repositories"
+	"slices"
+)
+
+type RollVerification struct {
+	Roll     models.GameRoll
+	Loadout  []string
+	Expected []int
+	Valid    bool
+}
+
+// Verification is the public audit of a game's rolls. The server seed and
+// the recomputed dice are only included once the game is finished.
+type Verification struct {
+	Game            *models.Game
+	Revealed        bool
+	SeedMatchesHash bool
+	Rolls           []RollVerification
+}
+
+type FairnessService struct {
+	gameRepo *repositories.GameRepository
+}
+
+func NewFairnessService(gameRepo *repositories.GameRepository) *FairnessService {
+	return &FairnessService{gameRepo: gameRepo}
+}
+
+func (service *FairnessService) Verify(code string) (*Verification, error) {
+	game, err := service.gameRepo.FindByCode(code)
+
+	if err != nil {
+		return nil, ErrGameNotFound
+	}
+
+	if !game.IsStarted() {
+		return nil, ErrGameNotStarted
+	}
+
+	rolls, err := service.gameRepo.FindRolls(game.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := service.gameRepo.FindPlayers(game.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &Verification{
+		Game:     game,
+		Revealed: game.IsFinished(),
+		Rolls:    make([]RollVerification, 0, len(rolls)),
+	}
+
+	if verification.Revealed {
+		verification.SeedMatchesHash = fairness.Hash(game.ServerSeed) == game.ServerSeedHash
+	}
+
+	for _, roll := range rolls {
+		item := RollVerification{Roll: roll, Loadout: playerLoadout(players, roll.UserID)}
+
+		if verification.Revealed {
+			if item.Expected, err = fairness.RollDice(game.ServerSeed, roll.ClientSeed, roll.Nonce, rolledDice(item.Loadout, roll)); err != nil {
+				return nil, err
+			}
+
+			item.Valid = slices.Equal(item.Expected, roll.Dice)
+		}
+
+		verification.Rolls = append(verification.Rolls, item)
+	}
+
+	return verification, nil
+}
+
+// rolledDice is the dice a roll was thrown with, taken from the roller's
+// loadout. Rolls recorded without slots were thrown with standard dice.
+func rolledDice(slugs []string, roll models.GameRoll) []fairness.Die {
+	if roll.Slots == nil {
+		return fairness.LoadoutDice(nil, make([]int, len(roll.Dice)))
+	}
+
+	return fairness.LoadoutDice(loadout.Resolve(slugs), roll.Slots)
+}
+
+func playerLoadout(players []models.GameUser, userId uint) []string {
+	if player := findPlayer(players, userId); player != nil {
+		return player.Loadout
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"app/models"
+	"app/repositories"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrFriendRequestNotFound = errors.New("friend request not found")
+	ErrFriendRequestExists   = errors.New("friend request already sent")
+	ErrFriendRequestHandled  = errors.New("friend request is no longer pending")
+	ErrCannotFriendSelf      = errors.New("you cannot add yourself as a friend")
+	ErrAlreadyFriends        = errors.New("you are already friends")
+	ErrNotFriendsYet         = errors.New("you are not friends")
+)
+
+type FriendService struct {
+	friendRepo *repositories.FriendRepository
+	userRepo   *repositories.UserRepository
+	transactor *repositories.Transactor
+}
+
+func NewFriendService(
+	friendRepo *repositories.FriendRepository,
+	userRepo *repositories.UserRepository,
+	transactor *repositories.Transactor,
+) *FriendService {
+	return &FriendService{
+		friendRepo: friendRepo,
+		userRepo:   userRepo,
+		transactor: transactor,
+	}
+}
+
+func (service *FriendService) ListFriends(authUser *models.User) ([]models.User, error) {
+	return service.friendRepo.FindFriends(authUser.ID)
+}
+
+func (service *FriendService) ListPending(authUser *models.User) ([]models.FriendRequest, error) {
+	return service.friendRepo.FindPending(authUser.ID)
+}
+
+// SendRequest asks username to become a friend. When that user has already
+// asked the sender, their request is accepted instead.
+func (service *FriendService) SendRequest(authUser *models.User, username string) (*models.FriendRequest, error) {
+	receiver, err := service.userRepo.FindByUsername(username)
+
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if receiver.ID == authUser.ID {
+		return nil, ErrCannotFriendSelf
+	}
+
+	friends, err := service.userRepo.AreFriends(authUser.ID, receiver.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if friends {
+		return nil, ErrAlreadyFriends
+	}
+
+	if _, err := service.friendRepo.FindPendingBetween(authUser.ID, receiver.ID); err == nil {
+		return nil, ErrFriendRequestExists
+	}
+
+	if incoming, err := service.friendRepo.FindPendingBetween(receiver.ID, authUser.ID); err == nil {
+		return service.respond(authUser, incoming.ID, models.FriendRequestAccepted)
+	}
+
+	request := &models.FriendRequest{
+		SenderID:   authUser.ID,
+		ReceiverID: receiver.ID,
+		Status:     models.FriendRequestPending,
+	}
+
+	if err := service.friendRepo.CreateRequest(request); err != nil {
+		return nil, err
+	}
+
+	request.Sender = *authUser
+	request.Receiver = *receiver
+
+	return request, nil
+}
+
+func (service *FriendService) Accept(authUser *models.User, requestId uint) (*models.FriendRequest, error) {
+	return service.respond(authUser, requestId, models.FriendRequestAccepted)
+}
+
+func (service *FriendService) Decline(authUser *models.User, requestId uint) (*models.FriendRequest, error) {
+	return service.respond(authUser, requestId, models.FriendRequestDeclined)
+}
+
+// Cancel withdraws a pending request the user has sent.
+func (service *FriendService) Cancel(authUser *models.User, requestId uint) error {
+	request, err := service.friendRepo.FindRequest(requestId)
+
+	if err != nil || request.SenderID != authUser.ID {
+		return ErrFriendRequestNotFound
+	}
+
+	if request.Status != models.FriendRequestPending {
+		return ErrFriendRequestHandled
+	}
+
+	return service.friendRepo.DeleteRequest(request.ID)
+}
+
+func (service *FriendService) Remove(authUser *models.User, friendId uint) error {
+	removed, err := service.friendRepo.RemoveFriendship(authUser.ID, friendId)
+
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return ErrNotFriendsYet
+	}
+
+	return nil
+}
+
+// respond settles a pending request addressed to the user. Accepting links
+// both users in one transaction so the friendship is always mutual.
+func (service *FriendService) respond(authUser *models.User, requestId uint, status string) (*models.FriendRequest, error) {
+	var request *models.FriendRequest
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		var err error
+		request, err = tx.Friends.FindRequest(requestId)
+
+		if err != nil || request.ReceiverID != authUser.ID {
+			return ErrFriendRequestNotFound
+		}
+
+		if request.Status != models.FriendRequestPending {
+			return ErrFriendRequestHandled
+		}
+
+		request.Status = status
+		request.RespondedAt = time.Now()
+
+		if err := tx.Friends.UpdateStatus(request); err != nil {
+			return err
+		}
+
+		if status != models.FriendRequestAccepted {
+			return nil
+		}
+
+		return tx.Friends.AddFriendship(request.SenderID, request.ReceiverID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
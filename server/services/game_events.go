@@ -0,0 +1,86 @@
+package services
+
+import (
+	"app/events"
+	"app/farkle"
+	"app/models"
+)
+
+// outcomeEvents translates a turn outcome into the events other players see.
+func outcomeEvents(snapshot *GameSnapshot, outcome farkle.Outcome) []events.Event {
+	game := snapshot.Game
+	player := outcome.PlayerID
+	var list []events.Event
+
+	switch outcome.Action {
+	case farkle.ActionRoll:
+		list = append(list, gameEvent(events.Rolled, game, player, outcome))
+
+		if outcome.Farkle {
+			list = append(list, gameEvent(events.Farkle, game, player, outcome))
+		}
+	case farkle.ActionReroll:
+		list = append(list, gameEvent(events.Rerolled, game, player, outcome))
+
+		if outcome.Farkle {
+			list = append(list, gameEvent(events.Farkle, game, player, outcome))
+		}
+	case farkle.ActionForfeit:
+		list = append(list, gameEvent(events.PlayerForfeited, game, player, outcome))
+	case farkle.ActionSetAside:
+		list = append(list, gameEvent(events.SetAside, game, player, outcome))
+	case farkle.ActionBank:
+		if outcome.Result != nil {
+			list = append(list, gameEvent(events.SetAside, game, player, outcome))
+		}
+
+		list = append(list, gameEvent(events.Banked, game, player, outcome))
+	}
+
+	if outcome.Penalty > 0 {
+		list = append(list, gameEvent(events.FarklePenalty, game, player, outcome))
+	}
+
+	if outcome.LastRound {
+		list = append(list, gameEvent(events.LastRound, game, player, outcome))
+	}
+
+	if outcome.TimedOut {
+		list = append(list, gameEvent(events.TurnTimedOut, game, player, outcome))
+	}
+
+	if outcome.HotDice {
+		list = append(list, gameEvent(events.HotDice, game, player, outcome))
+	}
+
+	if outcome.Finished {
+		list = append(list, gameEvent(events.GameOver, game, player, gameOverData(snapshot.Players)))
+	} else if outcome.NextPlayerID != player {
+		list = append(list, gameEvent(events.TurnChanged, game, outcome.NextPlayerID, nil))
+	}
+
+	return list
+}
+
+func gameOverData(players []models.GameUser) map[string]any {
+	data := map[string]any{}
+
+	for _, p := range players {
+		if p.IsWinner {
+			data["winner_id"] = p.UserID
+			data["payout"] = p.Payout
+		}
+	}
+
+	return data
+}
+
+func gameEvent(eventType events.Type, game *models.Game, userId uint, data any) events.Event {
+	return events.Event{
+		Type:     eventType,
+		GameID:   game.ID,
+		GameCode: game.Code,
+		UserID:   userId,
+		Data:     data,
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"app/events"
+	"app/models"
+	"app/repositories"
+	"time"
+)
+
+// exitGame takes the user out of a game that is still in play and settles
+// the escrow. A creator leaving a lobby calls the game off and everyone is
+// refunded, any other player leaving a lobby gets their stake back, and a
+// player leaving a started game forfeits it to the best placed opponent.
+func exitGame(tx *repositories.Tx, game *models.Game, userId uint) ([]events.Event, error) {
+	players, err := tx.Games.FindPlayers(game.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player := findPlayer(players, userId)
+
+	if player == nil {
+		return nil, ErrNotAPlayer
+	}
+
+	if game.IsStarted() {
+		return forfeitGame(tx, game, players, userId)
+	}
+
+	if game.CreatorID == userId {
+		if err := refundStakes(tx, game, players); err != nil {
+			return nil, err
+		}
+
+		game.CancelledAt = time.Now()
+
+		if err := tx.Games.MarkCancelled(game); err != nil {
+			return nil, err
+		}
+
+		return []events.Event{gameEvent(events.GameCancelled, game, userId, nil)}, nil
+	}
+
+	if err := unseat(tx, game, *player); err != nil {
+		return nil, err
+	}
+
+	return []events.Event{gameEvent(events.PlayerLeft, game, userId, nil)}, nil
+}
+
+// unseat refunds a player's stake and frees their seat in the lobby.
+func unseat(tx *repositories.Tx, game *models.Game, player models.GameUser) error {
+	if err := refundStakes(tx, game, []models.GameUser{player}); err != nil {
+		return err
+	}
+
+	return tx.Games.RemovePlayer(game.ID, player.UserID)
+}
+
+// forfeitGame takes the player out of a started game. Their stake stays in
+// the pot; once a single player is left the game is over and they win it.
+func forfeitGame(tx *repositories.Tx, game *models.Game, players []models.GameUser, userId uint) ([]events.Event, error) {
+	player := findPlayer(players, userId)
+
+	if player.HasForfeited() {
+		return nil, ErrAlreadyForfeited
+	}
+
+	match := newMatch(game, players)
+	outcome, err := match.Forfeit(userId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player.ForfeitedAt = time.Now()
+
+	if err := tx.Games.MarkForfeited(game.ID, userId, player.ForfeitedAt); err != nil {
+		return nil, err
+	}
+
+	if err := saveMatch(tx, game, players, match); err != nil {
+		return nil, err
+	}
+
+	return outcomeEvents(&GameSnapshot{Game: game, Players: players}, outcome), nil
+}
@@ -1,27 +1,65 @@
 package services
 
 import (
+	"app/events"
+	"app/farkle"
 	"app/http/inputs"
+	"app/loadout"
 	"app/models"
 	"app/repositories"
 	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrGameNotFound       = errors.New("game not found")
+	ErrGameNotStarted     = errors.New("game has not started")
+	ErrGameAlreadyStarted = errors.New("game has already started")
+	ErrGameCancelled      = errors.New("game has been cancelled")
+	ErrNotCreator         = errors.New("only the game creator can do this")
+	ErrNotAPlayer         = errors.New("you are not a player in this game")
+	ErrAlreadyJoined      = errors.New("you have already joined this game")
+	ErrGameFull           = errors.New("game is full")
+	ErrNotFriends         = errors.New("only friends of the creator can join this game")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrNotOwned           = errors.New("you do not own enough of these dice or this badge")
+	ErrInvalidCurrency    = errors.New("invalid currency")
+	ErrCreatorCannotLeave = errors.New("the creator cancels the game instead of leaving it")
+	ErrAlreadyForfeited   = errors.New("you have already forfeited this game")
+	ErrNotEnoughPlayers   = errors.New("not enough players have joined to start")
+	ErrPlayerNotFound     = errors.New("player not found")
+	ErrCannotKickCreator  = errors.New("the creator cannot be kicked")
+	ErrSeatsDrawn         = errors.New("seats are drawn at random in this game")
+	ErrInvalidSeatOrder   = errors.New("seat order must list every player once")
+	ErrSpectatingClosed   = errors.New("this game is closed to spectators")
+	ErrNotFriendsToWatch  = errors.New("only friends of the creator can watch this game")
 )
 
 type GameService struct {
 	balanceRepo  *repositories.BalanceRepository
 	currencyRepo *repositories.CurrencyRepository
 	gameRepo     *repositories.GameRepository
+	userRepo     *repositories.UserRepository
+	transactor   *repositories.Transactor
+	bus          *events.Bus
 }
 
 func NewGameService(
 	balanceRepo *repositories.BalanceRepository,
 	currencyRepo *repositories.CurrencyRepository,
 	gameRepo *repositories.GameRepository,
+	userRepo *repositories.UserRepository,
+	transactor *repositories.Transactor,
+	bus *events.Bus,
 ) *GameService {
 	return &GameService{
 		balanceRepo:  balanceRepo,
 		currencyRepo: currencyRepo,
 		gameRepo:     gameRepo,
+		userRepo:     userRepo,
+		transactor:   transactor,
+		bus:          bus,
 	}
 }
 
@@ -29,7 +67,7 @@ func (service *GameService) CreateGame(authUser *models.User, input *inputs.Crea
 	currency, err := service.currencyRepo.FindById(input.CurrencyID)
 
 	if err != nil {
-		return nil, errors.New("failed to get currency")
+		return nil, ErrInvalidCurrency
 	}
 
 	userBalance, err := service.balanceRepo.FindByUserAndCurrency(*authUser, currency.ID)
@@ -39,17 +77,465 @@ func (service *GameService) CreateGame(authUser *models.User, input *inputs.Crea
 	}
 
 	if userBalance.Amount < input.Bet {
-		return nil, errors.New("insufficient funds")
+		return nil, ErrInsufficientFunds
 	}
 
-	game, err := service.gameRepo.CreateGame(*authUser, *input)
+	var game *models.Game
 
-	if err != nil {
-		return nil, errors.New("failed to create game")
+	err = service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err = tx.Games.CreateGame(*authUser, *input)
 
+		if err != nil {
+			return errors.New("failed to create game")
+		}
+
+		return escrowStake(tx, game, authUser.ID)
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	game.Currency = currency
 
 	return game, nil
 }
+
+// ScoreSelection evaluates a selection of dice by the house rules of the
+// game, so a client can show the score the server will award for it.
+func (service *GameService) ScoreSelection(code string, dice []int) (farkle.Result, error) {
+	game, err := service.gameRepo.FindByCode(code)
+
+	if err != nil {
+		return farkle.Result{}, ErrGameNotFound
+	}
+
+	return farkle.Rules(game.Rules).Evaluate(dice), nil
+}
+
+// JoinGame seats the user in a game that has not started yet. Knowing the
+// code is enough for "anyone" and "link" games, which differ only in whether
+// the game is listed publicly; "friends" games also require the joiner to be
+// in the creator's friends list.
+func (service *GameService) JoinGame(authUser *models.User, code string) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkSeat(game, players, authUser.ID); err != nil {
+			return err
+		}
+
+		if game.JoinType == inputs.OnlyFriends {
+			friends, err := service.userRepo.AreFriends(game.CreatorID, authUser.ID)
+
+			if err != nil {
+				return err
+			}
+
+			if !friends {
+				return ErrNotFriends
+			}
+		}
+
+		if err := escrowStake(tx, game, authUser.ID); err != nil {
+			return err
+		}
+
+		players, err = tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.bus.Publish(gameEvent(events.PlayerJoined, snapshot.Game, authUser.ID, nil))
+
+	return snapshot, nil
+}
+
+// AddBot lets the creator seat a computer opponent playing the given
+// strategy. The house covers the bot's stake when its balance runs short.
+// Bots are seated ready, so the last bot to fill a ready table starts it.
+func (service *GameService) AddBot(authUser *models.User, code string, strategy string) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+	var bot *models.User
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.CreatorID != authUser.ID {
+			return ErrNotCreator
+		}
+
+		bot, err = tx.Users.FindOrCreateBot(strategy, botUsername(strategy))
+
+		if err != nil {
+			return err
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkSeat(game, players, bot.ID); err != nil {
+			return err
+		}
+
+		if err := fundBot(tx, game, bot); err != nil {
+			return err
+		}
+
+		if err := escrowStake(tx, game, bot.ID); err != nil {
+			return err
+		}
+
+		// bots are always ready
+		if err := tx.Games.UpdateReady(game.ID, bot.ID, time.Now()); err != nil {
+			return err
+		}
+
+		players, err = tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if isTableReady(game, players) {
+			if err := startGame(tx, game, players); err != nil {
+				return err
+			}
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.bus.Publish(gameEvent(events.PlayerJoined, snapshot.Game, bot.ID, nil))
+	service.publishStart(snapshot)
+
+	return snapshot, nil
+}
+
+// SetLoadout picks the dice and badge a player brings to a game that has
+// not started yet. Every special die and the badge must be in the player's
+// inventory; standard dice are always available.
+func (service *GameService) SetLoadout(authUser *models.User, code string, input inputs.LoadoutInput) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.IsCancelled() {
+			return ErrGameCancelled
+		}
+
+		if game.IsStarted() {
+			return ErrGameAlreadyStarted
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if !isPlayer(players, authUser.ID) {
+			return ErrNotAPlayer
+		}
+
+		items, err := tx.Inventory.FindByUser(authUser.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if !ownsLoadout(items, input) {
+			return ErrNotOwned
+		}
+
+		if err := tx.Games.UpdateLoadout(game.ID, authUser.ID, input.Dice, input.Badge); err != nil {
+			return err
+		}
+
+		players, err = tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// StartGame lets the creator start the game once enough players have
+// joined, without waiting for a full table or for everyone to be ready.
+func (service *GameService) StartGame(authUser *models.User, code string) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.CreatorID != authUser.ID {
+			return ErrNotCreator
+		}
+
+		if game.IsCancelled() {
+			return ErrGameCancelled
+		}
+
+		if game.IsStarted() {
+			return ErrGameAlreadyStarted
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if uint(len(players)) < game.Quorum() {
+			return ErrNotEnoughPlayers
+		}
+
+		if err := startGame(tx, game, players); err != nil {
+			return err
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.bus.Publish(gameEvent(events.GameStarted, snapshot.Game, snapshot.Game.State.CurrentUserID, nil))
+
+	return snapshot, nil
+}
+
+// CancelGame lets the creator call off a game that has not started yet and
+// refunds every escrowed stake.
+func (service *GameService) CancelGame(authUser *models.User, code string) error {
+	var cancelled *models.Game
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.CreatorID != authUser.ID {
+			return ErrNotCreator
+		}
+
+		if game.IsCancelled() {
+			return ErrGameCancelled
+		}
+
+		if game.IsStarted() {
+			return ErrGameAlreadyStarted
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := refundStakes(tx, game, players); err != nil {
+			return err
+		}
+
+		game.CancelledAt = time.Now()
+		cancelled = game
+
+		return tx.Games.MarkCancelled(game)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	service.bus.Publish(gameEvent(events.GameCancelled, cancelled, authUser.ID, nil))
+
+	return nil
+}
+
+// LeaveGame gives up a seat in a game that has not started and refunds
+// the stake.
+func (service *GameService) LeaveGame(authUser *models.User, code string) error {
+	var published []events.Event
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.IsCancelled() {
+			return ErrGameCancelled
+		}
+
+		if game.IsStarted() {
+			return ErrGameAlreadyStarted
+		}
+
+		if game.CreatorID == authUser.ID {
+			return ErrCreatorCannotLeave
+		}
+
+		published, err = exitGame(tx, game, authUser.ID)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	service.bus.Publish(published...)
+
+	return nil
+}
+
+// ForfeitGame gives up a started game. The stake is lost to the pot and
+// the game carries on without the player, or ends when one player is left.
+func (service *GameService) ForfeitGame(authUser *models.User, code string) error {
+	var published []events.Event
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.IsCancelled() {
+			return ErrGameCancelled
+		}
+
+		if !game.IsStarted() || game.State == nil {
+			return ErrGameNotStarted
+		}
+
+		if game.IsFinished() {
+			return farkle.ErrGameFinished
+		}
+
+		published, err = exitGame(tx, game, authUser.ID)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	service.bus.Publish(published...)
+
+	return nil
+}
+
+// checkSeat reports why the user cannot take a seat in the game, if anything.
+func checkSeat(game *models.Game, players []models.GameUser, userId uint) error {
+	if game.IsCancelled() {
+		return ErrGameCancelled
+	}
+
+	if game.IsStarted() {
+		return ErrGameAlreadyStarted
+	}
+
+	if isPlayer(players, userId) {
+		return ErrAlreadyJoined
+	}
+
+	if uint(len(players)) >= game.Capacity() {
+		return ErrGameFull
+	}
+
+	return nil
+}
+
+func botUsername(strategy string) string {
+	return strings.ToUpper(strategy[:1]) + strategy[1:] + " Bot"
+}
+
+func ownsLoadout(items []models.InventoryItem, input inputs.LoadoutInput) bool {
+	owned := make(map[string]uint, len(items))
+
+	for _, item := range items {
+		owned[item.Kind+":"+item.Slug] = item.Quantity
+	}
+
+	needed := make(map[string]uint)
+
+	for _, slug := range input.Dice {
+		if slug != loadout.StandardDie {
+			needed[models.ItemDie+":"+slug]++
+		}
+	}
+
+	if input.Badge != "" {
+		needed[models.ItemBadge+":"+input.Badge]++
+	}
+
+	for key, n := range needed {
+		if owned[key] < n {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"app/events"
+	"app/farkle"
+	"app/http/inputs"
+	"app/models"
+	"app/repositories"
+)
+
+// Watch loads a game for a user who follows it without a seat. Spectators
+// are held to the game's join type like joiners are, and the creator may
+// close the game to them altogether.
+func (service *GameService) Watch(authUser *models.User, code string) (*GameSnapshot, error) {
+	game, err := service.gameRepo.FindByCode(code)
+
+	if err != nil {
+		return nil, ErrGameNotFound
+	}
+
+	if game.IsCancelled() {
+		return nil, ErrGameCancelled
+	}
+
+	if !game.AllowsSpectators() {
+		return nil, ErrSpectatingClosed
+	}
+
+	if game.JoinType == inputs.OnlyFriends && game.CreatorID != authUser.ID {
+		friends, err := service.userRepo.AreFriends(game.CreatorID, authUser.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !friends {
+			return nil, ErrNotFriendsToWatch
+		}
+	}
+
+	players, err := service.gameRepo.FindPlayers(game.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &GameSnapshot{Game: game, Players: players}, nil
+}
+
+// SetSpectating lets the creator open the game to spectators or close it.
+// Closing it drops the spectators who are watching.
+func (service *GameService) SetSpectating(authUser *models.User, code string, allow bool) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.CreatorID != authUser.ID {
+			return ErrNotCreator
+		}
+
+		if game.IsCancelled() {
+			return ErrGameCancelled
+		}
+
+		if game.IsFinished() {
+			return farkle.ErrGameFinished
+		}
+
+		game.NoSpectators = !allow
+
+		if err := tx.Games.UpdateSpectating(game); err != nil {
+			return err
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := events.SpectatingClosed
+
+	if allow {
+		eventType = events.SpectatingOpened
+	}
+
+	service.bus.Publish(gameEvent(eventType, snapshot.Game, authUser.ID, nil))
+
+	return snapshot, nil
+}
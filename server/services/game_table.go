@@ -0,0 +1,260 @@
+package services
+
+import (
+	"app/events"
+	"app/fairness"
+	"app/farkle"
+	"app/http/inputs"
+	"app/models"
+	"app/repositories"
+	"math/rand/v2"
+	"time"
+)
+
+// SetReady marks the player ready or not ready in the lobby. The game
+// starts on its own once the table is full and every player is ready.
+func (service *GameService) SetReady(authUser *models.User, code string, ready bool) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, players, err := findLobby(tx, code)
+
+		if err != nil {
+			return err
+		}
+
+		player := findPlayer(players, authUser.ID)
+
+		if player == nil {
+			return ErrNotAPlayer
+		}
+
+		player.ReadyAt = time.Time{}
+
+		if ready {
+			player.ReadyAt = time.Now()
+		}
+
+		if err := tx.Games.UpdateReady(game.ID, player.UserID, player.ReadyAt); err != nil {
+			return err
+		}
+
+		if isTableReady(game, players) {
+			if err := startGame(tx, game, players); err != nil {
+				return err
+			}
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := events.PlayerUnready
+
+	if ready {
+		eventType = events.PlayerReady
+	}
+
+	service.bus.Publish(gameEvent(eventType, snapshot.Game, authUser.ID, nil))
+	service.publishStart(snapshot)
+
+	return snapshot, nil
+}
+
+// KickPlayer lets the creator take another player's seat away in the lobby.
+// The kicked player gets their stake back.
+func (service *GameService) KickPlayer(authUser *models.User, code string, userId uint) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, players, err := findLobby(tx, code)
+
+		if err != nil {
+			return err
+		}
+
+		if game.CreatorID != authUser.ID {
+			return ErrNotCreator
+		}
+
+		if userId == game.CreatorID {
+			return ErrCannotKickCreator
+		}
+
+		player := findPlayer(players, userId)
+
+		if player == nil {
+			return ErrPlayerNotFound
+		}
+
+		if err := unseat(tx, game, *player); err != nil {
+			return err
+		}
+
+		players, err = tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.bus.Publish(gameEvent(events.PlayerKicked, snapshot.Game, userId, nil))
+
+	return snapshot, nil
+}
+
+// SetSeats lets the creator arrange the turn order of a game with
+// host-defined seating. The order must list every seated player once.
+func (service *GameService) SetSeats(authUser *models.User, code string, order []uint) (*GameSnapshot, error) {
+	var snapshot *GameSnapshot
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, players, err := findLobby(tx, code)
+
+		if err != nil {
+			return err
+		}
+
+		if game.CreatorID != authUser.ID {
+			return ErrNotCreator
+		}
+
+		if game.Seating != inputs.SeatingHost {
+			return ErrSeatsDrawn
+		}
+
+		if len(order) != len(players) {
+			return ErrInvalidSeatOrder
+		}
+
+		for _, id := range order {
+			if !isPlayer(players, id) {
+				return ErrInvalidSeatOrder
+			}
+		}
+
+		if err := tx.Games.UpdateSeats(game.ID, order); err != nil {
+			return err
+		}
+
+		players, err = tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		snapshot = &GameSnapshot{Game: game, Players: players}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.bus.Publish(gameEvent(events.SeatsChanged, snapshot.Game, authUser.ID, playerIds(snapshot.Players)))
+
+	return snapshot, nil
+}
+
+// publishStart announces a game that an action has just started.
+func (service *GameService) publishStart(snapshot *GameSnapshot) {
+	if snapshot.Game.IsStarted() && snapshot.Game.State != nil {
+		service.bus.Publish(gameEvent(events.GameStarted, snapshot.Game, snapshot.Game.State.CurrentUserID, nil))
+	}
+}
+
+// findLobby loads a game that is still taking players, with its players.
+func findLobby(tx *repositories.Tx, code string) (*models.Game, []models.GameUser, error) {
+	game, err := tx.Games.FindByCode(code)
+
+	if err != nil {
+		return nil, nil, ErrGameNotFound
+	}
+
+	if game.IsCancelled() {
+		return nil, nil, ErrGameCancelled
+	}
+
+	if game.IsStarted() {
+		return nil, nil, ErrGameAlreadyStarted
+	}
+
+	players, err := tx.Games.FindPlayers(game.ID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return game, players, nil
+}
+
+// isTableReady reports whether every seat is taken by a ready player.
+func isTableReady(game *models.Game, players []models.GameUser) bool {
+	if uint(len(players)) < game.Capacity() {
+		return false
+	}
+
+	for _, p := range players {
+		if !p.IsReady() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// startGame draws the seats when they are random, commits to a fresh
+// server seed and deals the first turn to the first seat.
+func startGame(tx *repositories.Tx, game *models.Game, players []models.GameUser) error {
+	if game.Seating != inputs.SeatingHost {
+		rand.Shuffle(len(players), func(i, j int) {
+			players[i], players[j] = players[j], players[i]
+		})
+
+		for i := range players {
+			players[i].Seat = uint(i + 1)
+		}
+
+		if err := tx.Games.UpdateSeats(game.ID, playerIds(players)); err != nil {
+			return err
+		}
+	}
+
+	match, err := farkle.NewMatch(playerIds(players), game.WinningPoints, farkle.Rules(game.Rules))
+
+	if err != nil {
+		return err
+	}
+
+	serverSeed, err := fairness.NewServerSeed()
+
+	if err != nil {
+		return err
+	}
+
+	game.StartedAt = time.Now()
+	game.ServerSeed = serverSeed
+	game.ServerSeedHash = fairness.Hash(serverSeed)
+	game.State = &models.GameState{GameID: game.ID, Turn: 1, TurnStartedAt: game.StartedAt}
+	applyMatch(game.State, match)
+
+	if err := tx.Games.SaveState(game.State); err != nil {
+		return err
+	}
+
+	return tx.Games.MarkStarted(game)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"app/http/inputs"
+	"app/loadout"
+	"app/models"
+	"app/repositories"
+	"errors"
+)
+
+type InventoryService struct {
+	inventoryRepo *repositories.InventoryRepository
+	currencyRepo  *repositories.CurrencyRepository
+	transactor    *repositories.Transactor
+}
+
+func NewInventoryService(
+	inventoryRepo *repositories.InventoryRepository,
+	currencyRepo *repositories.CurrencyRepository,
+	transactor *repositories.Transactor,
+) *InventoryService {
+	return &InventoryService{
+		inventoryRepo: inventoryRepo,
+		currencyRepo:  currencyRepo,
+		transactor:    transactor,
+	}
+}
+
+func (service *InventoryService) List(authUser *models.User) ([]models.InventoryItem, error) {
+	return service.inventoryRepo.FindByUser(authUser.ID)
+}
+
+// Purchase pays for catalogue items from the user's balance and adds them
+// to the inventory.
+func (service *InventoryService) Purchase(authUser *models.User, input inputs.PurchaseInput) ([]models.InventoryItem, error) {
+	price, currencySlug := itemPrice(input.Kind, input.Slug)
+	currency, err := service.currencyRepo.FindBySlug(currencySlug)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.transactor.Transaction(func(tx *repositories.Tx) error {
+		err := tx.Balances.Debit(repositories.Movement{
+			UserID:     authUser.ID,
+			CurrencyID: currency.ID,
+			Amount:     price * input.Quantity,
+			Reason:     models.LedgerPurchase,
+		})
+
+		if errors.Is(err, repositories.ErrInsufficientBalance) {
+			return ErrInsufficientFunds
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Inventory.Add(authUser.ID, input.Kind, input.Slug, input.Quantity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return service.inventoryRepo.FindByUser(authUser.ID)
+}
+
+func itemPrice(kind, slug string) (uint, string) {
+	if kind == models.ItemBadge {
+		badge, _ := loadout.FindBadge(slug)
+
+		return badge.Price, badge.Currency
+	}
+
+	die, _ := loadout.FindDie(slug)
+
+	return die.Price, die.Currency
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"app/http/inputs"
+	"app/models"
+	"app/repositories"
+	"time"
+)
+
+// metricValues are the aggregates each leaderboard is ranked by.
+var metricValues = map[string]string{
+	inputs.MetricWinnings: "SUM(CAST(gu.payout AS INTEGER) - CAST(gu.stake AS INTEGER))",
+	inputs.MetricWins:     "SUM(CASE WHEN gu.is_winner THEN 1 ELSE 0 END)",
+	inputs.MetricWinRate:  "AVG(CASE WHEN gu.is_winner THEN 100.0 ELSE 0 END)",
+	inputs.MetricBestTurn: "MAX(gu.best_turn)",
+}
+
+type Leaderboard struct {
+	Since time.Time
+	Top   []repositories.LeaderboardRow
+	Me    *repositories.LeaderboardRow
+}
+
+type LeaderboardService struct {
+	leaderboardRepo *repositories.LeaderboardRepository
+}
+
+func NewLeaderboardService(leaderboardRepo *repositories.LeaderboardRepository) *LeaderboardService {
+	return &LeaderboardService{leaderboardRepo: leaderboardRepo}
+}
+
+// Rank returns the top of the leaderboard together with the caller's own row.
+func (service *LeaderboardService) Rank(authUser *models.User, input inputs.LeaderboardInput) (*Leaderboard, error) {
+	filter := repositories.LeaderboardFilter{
+		Value:      metricValues[input.Metric],
+		Since:      windowStart(input.Window, time.Now().UTC()),
+		CurrencyID: input.CurrencyID,
+		MinGames:   input.MinGames,
+	}
+
+	top, err := service.leaderboardRepo.FindTop(filter, input.Limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := service.leaderboardRepo.FindUser(filter, authUser.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Leaderboard{Since: filter.Since, Top: top, Me: me}, nil
+}
+
+// windowStart is the beginning of the current calendar month or ISO week.
+func windowStart(window string, now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch window {
+	case inputs.WindowMonthly:
+		return today.AddDate(0, 0, 1-today.Day())
+	case inputs.WindowWeekly:
+		return today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	default:
+		return time.Time{}
+	}
+}
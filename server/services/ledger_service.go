@@ -0,0 +1,19 @@
+package services
+
+import (
+	"app/http/inputs"
+	"app/models"
+	"app/repositories"
+)
+
+type LedgerService struct {
+	ledgerRepo *repositories.LedgerRepository
+}
+
+func NewLedgerService(ledgerRepo *repositories.LedgerRepository) *LedgerService {
+	return &LedgerService{ledgerRepo: ledgerRepo}
+}
+
+func (service *LedgerService) History(authUser *models.User, page inputs.PaginationInput) ([]models.LedgerEntry, int64, error) {
+	return service.ledgerRepo.FindUserEntries(authUser.ID, page.Offset(), page.PerPage)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"app/http/inputs"
+	"app/models"
+	"app/repositories"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// lobbyCursor points at the last game of a page.
+type lobbyCursor struct {
+	ID    uint `json:"id"`
+	Value uint `json:"v"`
+}
+
+type LobbyService struct {
+	gameRepo *repositories.GameRepository
+}
+
+func NewLobbyService(gameRepo *repositories.GameRepository) *LobbyService {
+	return &LobbyService{gameRepo: gameRepo}
+}
+
+// ListOpenGames returns a page of joinable public games and the cursor of
+// the next page, which is empty on the last page.
+func (service *LobbyService) ListOpenGames(input inputs.ListGamesInput) ([]models.Game, string, error) {
+	filter := repositories.LobbyFilter{
+		CurrencyID: input.CurrencyID,
+		MinBet:     input.MinBet,
+		MaxBet:     input.MaxBet,
+		Column:     "id",
+		Desc:       input.Order == inputs.OrderDesc,
+		Limit:      input.Limit + 1,
+	}
+
+	switch input.Sort {
+	case inputs.SortNewest:
+		filter.Desc = true
+	case inputs.SortOldest:
+		filter.Desc = false
+	case inputs.SortBet:
+		filter.Column = "bet"
+	case inputs.SortWinningPoints:
+		filter.Column = "winning_points"
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeCursor(input.Cursor)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		filter.AfterID = cursor.ID
+		filter.AfterValue = cursor.Value
+	}
+
+	games, err := service.gameRepo.FindOpenGames(inputs.Anyone, filter)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(games) <= input.Limit {
+		return games, "", nil
+	}
+
+	games = games[:input.Limit]
+	last := games[len(games)-1]
+	next := lobbyCursor{ID: last.ID}
+
+	switch filter.Column {
+	case "bet":
+		next.Value = last.Bet
+	case "winning_points":
+		next.Value = last.WinningPoints
+	}
+
+	return games, encodeCursor(next), nil
+}
+
+func encodeCursor(cursor lobbyCursor) string {
+	raw, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(value string) (lobbyCursor, error) {
+	var cursor lobbyCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == 0 {
+		return cursor, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"app/models"
+	"app/ratelimit"
+	"app/repositories"
+	"fmt"
+	"log"
+	"time"
+)
+
+var (
+	// UsernamePolicy locks an account after five failed logins in a row.
+	UsernamePolicy = ratelimit.Policy{
+		FreeAttempts: 2,
+		MaxAttempts:  5,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+		Lockout:      15 * time.Minute,
+		Window:       15 * time.Minute,
+	}
+	// LoginIPPolicy slows down password spraying across many accounts.
+	LoginIPPolicy = ratelimit.Policy{
+		FreeAttempts: 10,
+		MaxAttempts:  50,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Minute,
+		Lockout:      30 * time.Minute,
+		Window:       time.Hour,
+	}
+	// RegisterIPPolicy counts every registration, successful or not.
+	RegisterIPPolicy = ratelimit.Policy{
+		FreeAttempts: 3,
+		MaxAttempts:  10,
+		BaseDelay:    10 * time.Second,
+		MaxDelay:     5 * time.Minute,
+		Lockout:      time.Hour,
+		Window:       time.Hour,
+	}
+)
+
+// LoginGuard throttles logins per username and per IP, and registrations
+// per IP. Lockouts and their clearing are written to the audit log.
+type LoginGuard struct {
+	usernames *ratelimit.Limiter
+	loginIPs  *ratelimit.Limiter
+	signups   *ratelimit.Limiter
+	auditRepo *repositories.AuditRepository
+}
+
+func NewLoginGuard(store ratelimit.Store, auditRepo *repositories.AuditRepository) *LoginGuard {
+	return &LoginGuard{
+		usernames: ratelimit.NewLimiter(store, UsernamePolicy),
+		loginIPs:  ratelimit.NewLimiter(store, LoginIPPolicy),
+		signups:   ratelimit.NewLimiter(store, RegisterIPPolicy),
+		auditRepo: auditRepo,
+	}
+}
+
+// AttemptLogin counts a login for the username from the IP before the
+// password is checked, and is how long the login must wait when it may
+// not go ahead. user is nil when no account matched.
+func (guard *LoginGuard) AttemptLogin(username, ip string, user *models.User) time.Duration {
+	byIP := guard.loginIPs.Attempt(ratelimit.Key("login:ip", ip))
+
+	if byIP.Locked {
+		guard.audit(models.AuditLoginLocked, nil, "", ip,
+			fmt.Sprintf("ip locked after %d failed logins", byIP.Attempts))
+	}
+
+	if !byIP.Allowed {
+		return byIP.RetryAfter
+	}
+
+	byUser := guard.usernames.Attempt(ratelimit.Key("login:user", username))
+
+	if byUser.Locked {
+		guard.audit(models.AuditLoginLocked, user, username, ip,
+			fmt.Sprintf("username locked after %d failed logins", byUser.Attempts))
+	}
+
+	if !byUser.Allowed {
+		return byUser.RetryAfter
+	}
+
+	return 0
+}
+
+// LoginSucceeded clears the username's failures and takes back the IP's
+// count of this login. The IP keeps its failures so signing into one
+// account cannot reset spraying against others.
+func (guard *LoginGuard) LoginSucceeded(user *models.User, ip string) {
+	guard.loginIPs.Refund(ratelimit.Key("login:ip", ip))
+
+	if guard.usernames.Reset(ratelimit.Key("login:user", user.Username)) {
+		guard.audit(models.AuditLockoutCleared, user, user.Username, ip, "cleared by successful login")
+	}
+}
+
+// AttemptRegistration counts a registration from the IP, successful or
+// not, and is how long it must wait when it may not go ahead.
+func (guard *LoginGuard) AttemptRegistration(ip string) time.Duration {
+	result := guard.signups.Attempt(ratelimit.Key("register:ip", ip))
+
+	if result.Locked {
+		guard.audit(models.AuditLoginLocked, nil, "", ip,
+			fmt.Sprintf("ip locked after %d registrations", result.Attempts))
+	}
+
+	if !result.Allowed {
+		return result.RetryAfter
+	}
+
+	return 0
+}
+
+func (guard *LoginGuard) audit(event string, user *models.User, username, ip, detail string) {
+	entry := &models.AuditLog{
+		Event:    event,
+		Username: username,
+		IP:       ip,
+		Detail:   detail,
+	}
+
+	if user != nil {
+		entry.UserID = &user.ID
+	}
+
+	if err := guard.auditRepo.Create(entry); err != nil {
+		log.Println("audit: failed to record", event, err)
+	}
+}
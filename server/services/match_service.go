@@ -0,0 +1,38 @@
+package services
+
+import (
+	"app/http/inputs"
+	"app/models"
+	"app/repositories"
+)
+
+type MatchService struct {
+	gameRepo *repositories.GameRepository
+	userRepo *repositories.UserRepository
+}
+
+func NewMatchService(gameRepo *repositories.GameRepository, userRepo *repositories.UserRepository) *MatchService {
+	return &MatchService{
+		gameRepo: gameRepo,
+		userRepo: userRepo,
+	}
+}
+
+// History returns a page of the user's finished games.
+func (service *MatchService) History(userId uint, input inputs.MatchHistoryInput) ([]models.Game, int64, error) {
+	if _, err := service.userRepo.FindById(userId); err != nil {
+		return nil, 0, ErrUserNotFound
+	}
+
+	var won *bool
+
+	switch input.Result {
+	case inputs.ResultWin:
+		won = new(bool)
+		*won = true
+	case inputs.ResultLoss:
+		won = new(bool)
+	}
+
+	return service.gameRepo.FindFinishedForUser(userId, won, input.Offset(), input.PerPage)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"app/models"
+	"app/repositories"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionEnded        = errors.New("session has ended")
+)
+
+// Tokens is a short-lived access token and the refresh token to renew it.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// Client identifies the device a session was opened from.
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+type SessionService struct {
+	sessionRepo *repositories.SessionRepository
+	userRepo    *repositories.UserRepository
+	transactor  *repositories.Transactor
+	secret      []byte
+}
+
+func NewSessionService(
+	sessionRepo *repositories.SessionRepository,
+	userRepo *repositories.UserRepository,
+	transactor *repositories.Transactor,
+	secret string,
+) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		transactor:  transactor,
+		secret:      []byte(secret),
+	}
+}
+
+// SignIn opens a new session for the user.
+func (service *SessionService) SignIn(user models.User, client Client) (*Tokens, error) {
+	return service.issue(service.sessionRepo, user.ID, uuid.New().String(), client)
+}
+
+// Refresh exchanges a refresh token for a new pair. The old session is
+// revoked; presenting a revoked refresh token again means it was stolen, so
+// the whole family is revoked and the user has to sign in again. That
+// includes losing a race with another refresh of the same token.
+func (service *SessionService) Refresh(refreshToken string, client Client) (*Tokens, error) {
+	var tokens *Tokens
+	var reused *models.Session
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		session, err := tx.Sessions.FindByRefreshTokenHash(hashToken(refreshToken))
+
+		if err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		if session.IsRevoked() {
+			reused = session
+
+			return ErrInvalidRefreshToken
+		}
+
+		if !session.IsActive() {
+			return ErrInvalidRefreshToken
+		}
+
+		err = tx.Sessions.Revoke(session.ID)
+
+		if errors.Is(err, repositories.ErrSessionRevoked) {
+			reused = session
+
+			return ErrInvalidRefreshToken
+		}
+
+		if err != nil {
+			return err
+		}
+
+		tokens, err = service.issue(tx.Sessions, session.UserID, session.FamilyID, client)
+
+		return err
+	})
+
+	if reused != nil {
+		if err := service.sessionRepo.RevokeFamily(reused.FamilyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// SignOut ends the session family the access token belongs to.
+func (service *SessionService) SignOut(sessionId uint) error {
+	session, err := service.sessionRepo.FindById(sessionId)
+
+	if err != nil {
+		return ErrInvalidRefreshToken
+	}
+
+	return service.sessionRepo.RevokeFamily(session.FamilyID)
+}
+
+// SignOutEverywhere ends every session of the user.
+func (service *SessionService) SignOutEverywhere(userId uint) error {
+	return service.sessionRepo.RevokeUser(userId)
+}
+
+// Authenticate returns the user behind an access token as long as its
+// session is still active.
+func (service *SessionService) Authenticate(sessionId, userId uint) (*models.User, error) {
+	session, err := service.sessionRepo.FindById(sessionId)
+
+	if err != nil || !session.IsActive() || session.UserID != userId {
+		return nil, ErrSessionEnded
+	}
+
+	return service.userRepo.FindWithBalance(userId, models.GOLD)
+}
+
+func (service *SessionService) issue(
+	sessionRepo *repositories.SessionRepository,
+	userId uint,
+	familyId string,
+	client Client,
+) (*Tokens, error) {
+	refreshToken, err := randomToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		UserID:           userId,
+		FamilyID:         familyId,
+		RefreshTokenHash: hashToken(refreshToken),
+		UserAgent:        truncate(client.UserAgent, 255),
+		IP:               truncate(client.IP, 64),
+		ExpiresAt:        time.Now().Add(RefreshTokenTTL),
+	}
+
+	if err := sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(AccessTokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userId,
+		"sid": session.ID,
+		"exp": expiresAt.Unix(),
+	})
+
+	accessToken, err := token.SignedString(service.secret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken is how refresh tokens are stored, so a database leak does not
+// leak usable tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(value string, n int) string {
+	if len(value) > n {
+		return value[:n]
+	}
+
+	return value
+}
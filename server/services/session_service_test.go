@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"app/database"
+	"app/models"
+	"app/repositories"
+	"app/services"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// openDB returns a migrated in-memory database private to the test.
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := database.Open("file:" + t.Name() + "?mode=memory&cache=shared")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func newSessionService(t *testing.T) (*services.SessionService, models.User) {
+	db := openDB(t)
+	userRepo := repositories.NewUserRepository(db)
+	user := models.User{Username: "alice", Password: "hash"}
+
+	if err := userRepo.Create(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	service := services.NewSessionService(
+		repositories.NewSessionRepository(db),
+		userRepo,
+		repositories.NewTransactor(db),
+		"secret",
+	)
+
+	return service, user
+}
+
+func TestRefreshRotatesTheToken(t *testing.T) {
+	service, user := newSessionService(t)
+	tokens, err := service.SignIn(user, services.Client{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := service.Refresh(tokens.RefreshToken, services.Client{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatal("Refresh returned the same refresh token")
+	}
+
+	if _, err := service.Refresh(refreshed.RefreshToken, services.Client{}); err != nil {
+		t.Fatalf("refreshing the new token: %v", err)
+	}
+}
+
+func TestRefreshReuseEndsTheFamily(t *testing.T) {
+	service, user := newSessionService(t)
+	tokens, err := service.SignIn(user, services.Client{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := service.Refresh(tokens.RefreshToken, services.Client{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.Refresh(tokens.RefreshToken, services.Client{}); !errors.Is(err, services.ErrInvalidRefreshToken) {
+		t.Fatalf("reusing a refresh token = %v", err)
+	}
+
+	if _, err := service.Refresh(refreshed.RefreshToken, services.Client{}); !errors.Is(err, services.ErrInvalidRefreshToken) {
+		t.Fatalf("refresh after a reuse = %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"app/events"
+	"app/models"
+	"app/repositories"
+	"time"
+)
+
+// Expire acts for the player whose turn has run out. While they have time
+// left in their bank the turn is banked for them, or passed when there is
+// nothing to bank; once the bank is spent they forfeit the game. Nothing
+// happens before the deadline, so a stale timer is harmless.
+func (service *TurnService) Expire(code string) error {
+	var published []events.Event
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if game.IsCancelled() || !game.IsStarted() || game.State == nil {
+			return nil
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		player := findPlayer(players, game.State.CurrentUserID)
+
+		if player == nil {
+			return nil
+		}
+
+		deadline := game.TurnDeadline(*player)
+		now := time.Now()
+
+		if deadline.IsZero() || now.Before(deadline) {
+			return nil
+		}
+
+		if game.GameSeconds > 0 && now.Sub(game.State.TurnStartedAt) >= game.TimeLeft(*player) {
+			published, err = forfeitGame(tx, game, players, player.UserID)
+
+			return err
+		}
+
+		match := newMatch(game, players)
+		outcome, err := match.TimeOut(player.UserID)
+
+		if err != nil {
+			return err
+		}
+
+		if outcome.Banked > player.BestTurn {
+			player.BestTurn = outcome.Banked
+
+			if err := tx.Games.UpdateBestTurn(game.ID, player.UserID, player.BestTurn); err != nil {
+				return err
+			}
+		}
+
+		if err := saveMatch(tx, game, players, match); err != nil {
+			return err
+		}
+
+		published = outcomeEvents(&GameSnapshot{Game: game, Players: players}, outcome)
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	service.bus.Publish(published...)
+
+	return nil
+}
+
+// Abandon forfeits a timed game in play for a player who went away at
+// leftAt and did not come back within the grace period. Leaving the lobby
+// before the game started costs nothing.
+func (service *TurnService) Abandon(code string, userId uint, leftAt time.Time) error {
+	var published []events.Event
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if !game.IsTimed() || game.IsCancelled() || game.IsFinished() || !game.IsStarted() || game.State == nil {
+			return nil
+		}
+
+		if leftAt.Before(game.StartedAt) {
+			return nil
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		player := findPlayer(players, userId)
+
+		if player == nil || player.HasForfeited() {
+			return nil
+		}
+
+		published, err = forfeitGame(tx, game, players, userId)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	service.bus.Publish(published...)
+
+	return nil
+}
+
+// chargeTurn takes the time the current player spent on the turn that is
+// ending out of their time bank and starts the clock for the next turn.
+func chargeTurn(tx *repositories.Tx, game *models.Game, players []models.GameUser) error {
+	now := time.Now()
+	started := game.State.TurnStartedAt
+	game.State.TurnStartedAt = now
+	player := findPlayer(players, game.State.CurrentUserID)
+
+	if player == nil || started.IsZero() {
+		return nil
+	}
+
+	player.TimeUsed += now.Sub(started)
+
+	return tx.Games.UpdateTimeUsed(game.ID, player.UserID, player.TimeUsed)
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"app/events"
+	"app/fairness"
+	"app/farkle"
+	"app/loadout"
+	"app/models"
+	"app/repositories"
+	"time"
+)
+
+// GameSnapshot is a game together with its players in turn order.
+type GameSnapshot struct {
+	Game    *models.Game
+	Players []models.GameUser
+}
+
+type TurnResult struct {
+	Outcome  farkle.Outcome
+	Snapshot *GameSnapshot
+}
+
+// turnAction applies a single action to a loaded match inside the turn transaction.
+type turnAction func(
+	tx *repositories.Tx,
+	game *models.Game,
+	player *models.GameUser,
+	match *farkle.Match,
+) (farkle.Outcome, error)
+
+type TurnService struct {
+	gameRepo   *repositories.GameRepository
+	transactor *repositories.Transactor
+	bus        *events.Bus
+}
+
+func NewTurnService(
+	gameRepo *repositories.GameRepository,
+	transactor *repositories.Transactor,
+	bus *events.Bus,
+) *TurnService {
+	return &TurnService{
+		gameRepo:   gameRepo,
+		transactor: transactor,
+		bus:        bus,
+	}
+}
+
+func (service *TurnService) GetState(authUser *models.User, code string) (*GameSnapshot, error) {
+	game, err := service.gameRepo.FindByCode(code)
+
+	if err != nil {
+		return nil, ErrGameNotFound
+	}
+
+	players, err := service.gameRepo.FindPlayers(game.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isPlayer(players, authUser.ID) {
+		return nil, ErrNotAPlayer
+	}
+
+	return &GameSnapshot{Game: game, Players: players}, nil
+}
+
+// Roll throws the dice from the committed server seed, the player's client
+// seed and the next nonce, and records the roll for later verification.
+// An empty clientSeed keeps the seed the player used last.
+func (service *TurnService) Roll(authUser *models.User, code string, clientSeed string) (*TurnResult, error) {
+	return service.throw(authUser, code, clientSeed, func(match *farkle.Match, roller farkle.Roller) (farkle.Outcome, error) {
+		return match.Roll(authUser.ID, roller)
+	})
+}
+
+// Reroll throws one die on the table again with the player's reroll badge.
+func (service *TurnService) Reroll(authUser *models.User, code string, value int, clientSeed string) (*TurnResult, error) {
+	return service.throw(authUser, code, clientSeed, func(match *farkle.Match, roller farkle.Roller) (farkle.Outcome, error) {
+		return match.Reroll(authUser.ID, value, roller)
+	})
+}
+
+// throw runs an action that rolls dice from the player's loadout and
+// records every throw it made.
+func (service *TurnService) throw(
+	authUser *models.User,
+	code string,
+	clientSeed string,
+	roll func(match *farkle.Match, roller farkle.Roller) (farkle.Outcome, error),
+) (*TurnResult, error) {
+	return service.act(authUser, code, func(
+		tx *repositories.Tx,
+		game *models.Game,
+		player *models.GameUser,
+		match *farkle.Match,
+	) (farkle.Outcome, error) {
+		if clientSeed == "" {
+			clientSeed = player.ClientSeed
+		}
+
+		if clientSeed == "" {
+			seed, err := fairness.NewClientSeed()
+
+			if err != nil {
+				return farkle.Outcome{}, err
+			}
+
+			clientSeed = seed
+		}
+
+		roller := &fairness.Roller{
+			ServerSeed: game.ServerSeed,
+			ClientSeed: clientSeed,
+			Nonce:      game.State.Nonce,
+			Loadout:    loadout.Resolve(player.Loadout),
+		}
+
+		outcome, err := roll(match, roller)
+
+		if err != nil {
+			return outcome, err
+		}
+
+		game.State.Nonce = roller.Nonce
+
+		if player.ClientSeed != clientSeed {
+			if err := tx.Games.UpdateClientSeed(game.ID, player.UserID, clientSeed); err != nil {
+				return outcome, err
+			}
+
+			player.ClientSeed = clientSeed
+		}
+
+		for _, t := range roller.Throws {
+			err := tx.Games.CreateRoll(&models.GameRoll{
+				GameID:     game.ID,
+				Nonce:      t.Nonce,
+				UserID:     authUser.ID,
+				ClientSeed: clientSeed,
+				Slots:      t.Slots,
+				Dice:       t.Dice,
+			})
+
+			if err != nil {
+				return outcome, err
+			}
+		}
+
+		return outcome, nil
+	})
+}
+
+func (service *TurnService) SetAside(authUser *models.User, code string, dice []int) (*TurnResult, error) {
+	return service.act(authUser, code, func(
+		_ *repositories.Tx,
+		_ *models.Game,
+		_ *models.GameUser,
+		match *farkle.Match,
+	) (farkle.Outcome, error) {
+		return match.SetAside(authUser.ID, dice)
+	})
+}
+
+// Bank banks the turn score and remembers it when it is the player's best
+// turn of the game.
+func (service *TurnService) Bank(authUser *models.User, code string, dice []int) (*TurnResult, error) {
+	return service.act(authUser, code, func(
+		tx *repositories.Tx,
+		game *models.Game,
+		player *models.GameUser,
+		match *farkle.Match,
+	) (farkle.Outcome, error) {
+		outcome, err := match.Bank(authUser.ID, dice)
+
+		if err != nil || outcome.Banked <= player.BestTurn {
+			return outcome, err
+		}
+
+		player.BestTurn = outcome.Banked
+
+		return outcome, tx.Games.UpdateBestTurn(game.ID, player.UserID, player.BestTurn)
+	})
+}
+
+// act loads the match, applies a single action and persists the result
+// in one transaction so concurrent requests cannot interleave. Events are
+// published once the transaction has committed.
+func (service *TurnService) act(authUser *models.User, code string, action turnAction) (*TurnResult, error) {
+	var result *TurnResult
+
+	err := service.transactor.Transaction(func(tx *repositories.Tx) error {
+		game, err := tx.Games.FindByCode(code)
+
+		if err != nil {
+			return ErrGameNotFound
+		}
+
+		if !game.IsStarted() || game.State == nil {
+			return ErrGameNotStarted
+		}
+
+		players, err := tx.Games.FindPlayers(game.ID)
+
+		if err != nil {
+			return err
+		}
+
+		player := findPlayer(players, authUser.ID)
+
+		if player == nil {
+			return ErrNotAPlayer
+		}
+
+		match := newMatch(game, players)
+		outcome, err := action(tx, game, player, match)
+
+		if err != nil {
+			return err
+		}
+
+		if err := saveMatch(tx, game, players, match); err != nil {
+			return err
+		}
+
+		result = &TurnResult{
+			Outcome:  outcome,
+			Snapshot: &GameSnapshot{Game: game, Players: players},
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.bus.Publish(outcomeEvents(result.Snapshot, result.Outcome)...)
+
+	return result, nil
+}
+
+func newMatch(game *models.Game, players []models.GameUser) *farkle.Match {
+	totals := make(map[uint]uint, len(players))
+	badges := make(map[uint]farkle.Badge, len(players))
+	badgesUsed := make(map[uint]bool, len(players))
+	farkles := make(map[uint]int, len(players))
+	var winner uint
+
+	for _, p := range players {
+		totals[p.UserID] = p.Score
+		badges[p.UserID] = farkle.Badge(p.Badge)
+		badgesUsed[p.UserID] = p.BadgeUsed
+		farkles[p.UserID] = int(p.Farkles)
+
+		if p.IsWinner {
+			winner = p.UserID
+		}
+	}
+
+	return &farkle.Match{
+		Players:       playerIds(activePlayers(players)),
+		Totals:        totals,
+		Current:       game.State.CurrentUserID,
+		Phase:         farkle.Phase(game.State.Phase),
+		Dice:          game.State.Dice,
+		Slots:         game.State.Slots,
+		DiceLeft:      int(game.State.DiceLeft),
+		TurnScore:     game.State.TurnScore,
+		WinningPoints: game.WinningPoints,
+		Winner:        winner,
+		Badges:        badges,
+		BadgesUsed:    badgesUsed,
+		Rules:         farkle.Rules(game.Rules),
+		Farkles:       farkles,
+		LastRoundBy:   game.State.LastRoundBy,
+		LastTurns:     game.State.LastTurns,
+	}
+}
+
+func saveMatch(tx *repositories.Tx, game *models.Game, players []models.GameUser, match *farkle.Match) error {
+	if game.State.CurrentUserID != match.Current || match.Phase == farkle.PhaseFinished {
+		if err := chargeTurn(tx, game, players); err != nil {
+			return err
+		}
+	}
+
+	if game.State.CurrentUserID != match.Current {
+		game.State.Turn++
+	}
+
+	applyMatch(game.State, match)
+
+	if err := tx.Games.SaveState(game.State); err != nil {
+		return err
+	}
+
+	for i := range players {
+		if match.BadgesUsed[players[i].UserID] && !players[i].BadgeUsed {
+			if err := tx.Games.MarkBadgeUsed(game.ID, players[i].UserID); err != nil {
+				return err
+			}
+
+			players[i].BadgeUsed = true
+		}
+
+		if farkles := uint(match.Farkles[players[i].UserID]); players[i].Farkles != farkles {
+			if err := tx.Games.UpdateFarkles(game.ID, players[i].UserID, farkles); err != nil {
+				return err
+			}
+
+			players[i].Farkles = farkles
+		}
+
+		total := match.Totals[players[i].UserID]
+
+		if players[i].Score == total {
+			continue
+		}
+
+		if err := tx.Games.UpdateScore(game.ID, players[i].UserID, total); err != nil {
+			return err
+		}
+
+		players[i].Score = total
+	}
+
+	if match.Phase != farkle.PhaseFinished || game.IsFinished() {
+		return nil
+	}
+
+	game.FinishedAt = time.Now()
+
+	for i := range players {
+		players[i].IsWinner = players[i].UserID == match.Winner
+	}
+
+	if err := tx.Games.MarkFinished(game, match.Winner); err != nil {
+		return err
+	}
+
+	return payOut(tx, game, players, match.Winner)
+}
+
+func applyMatch(state *models.GameState, match *farkle.Match) {
+	state.CurrentUserID = match.Current
+	state.Phase = string(match.Phase)
+	state.Dice = match.Dice
+	state.Slots = match.Slots
+	state.DiceLeft = uint(match.DiceLeft)
+	state.TurnScore = match.TurnScore
+	state.LastRoundBy = match.LastRoundBy
+	state.LastTurns = match.LastTurns
+}
+
+func playerIds(players []models.GameUser) []uint {
+	ids := make([]uint, len(players))
+
+	for i, p := range players {
+		ids[i] = p.UserID
+	}
+
+	return ids
+}
+
+// activePlayers are the players still taking turns.
+func activePlayers(players []models.GameUser) []models.GameUser {
+	var active []models.GameUser
+
+	for _, p := range players {
+		if !p.HasForfeited() {
+			active = append(active, p)
+		}
+	}
+
+	return active
+}
+
+func isPlayer(players []models.GameUser, userId uint) bool {
+	return findPlayer(players, userId) != nil
+}
+
+func findPlayer(players []models.GameUser, userId uint) *models.GameUser {
+	for i := range players {
+		if players[i].UserID == userId {
+			return &players[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+// Package timers runs the turn clocks of timed games and steps in for
+// players who let their time run out or walk away from the table.
+package timers
+
+import (
+	"app/events"
+	"app/repositories"
+	"app/services"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultGrace is how long a player may stay disconnected from a timed
+// game in play before they forfeit it.
+const DefaultGrace = 60 * time.Second
+
+const (
+	queueSize = 256
+	// enqueueTimeout is how long a publisher is held back by a full queue
+	// before the event is given up on.
+	enqueueTimeout = 5 * time.Second
+)
+
+type absence struct {
+	code   string
+	userId uint
+}
+
+// Scheduler keeps one timer per timed game, armed for the current turn's
+// deadline, and one per player who has disconnected from a game. Timers
+// only ask the turn service to act; it checks the clock again itself.
+// Events are handled in order off the publisher's goroutine, as arming a
+// timer reads the game.
+type Scheduler struct {
+	turnService *services.TurnService
+	gameRepo    *repositories.GameRepository
+	grace       time.Duration
+	queue       chan events.Event
+
+	mu     sync.Mutex
+	turns  map[string]*time.Timer
+	absent map[absence]*time.Timer
+}
+
+func NewScheduler(
+	turnService *services.TurnService,
+	gameRepo *repositories.GameRepository,
+	bus *events.Bus,
+	grace time.Duration,
+) *Scheduler {
+	scheduler := &Scheduler{
+		turnService: turnService,
+		gameRepo:    gameRepo,
+		grace:       grace,
+		queue:       make(chan events.Event, queueSize),
+		turns:       make(map[string]*time.Timer),
+		absent:      make(map[absence]*time.Timer),
+	}
+
+	bus.Subscribe(scheduler.enqueue)
+	go scheduler.run()
+
+	return scheduler
+}
+
+// Start arms the clocks of the timed games that were in play when the
+// server last stopped.
+func (scheduler *Scheduler) Start() error {
+	games, err := scheduler.gameRepo.FindTimedInPlay()
+
+	if err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		scheduler.schedule(game.Code)
+	}
+
+	return nil
+}
+
+func (scheduler *Scheduler) enqueue(event events.Event) {
+	switch event.Type {
+	case events.GameStarted, events.TurnChanged, events.GameOver, events.GameCancelled,
+		events.PlayerDisconnected, events.PlayerConnected, events.PlayerForfeited:
+	default:
+		return
+	}
+
+	select {
+	case scheduler.queue <- event:
+		return
+	default:
+	}
+
+	// a lost event would leave a turn or an absence without its timer
+	timer := time.NewTimer(enqueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case scheduler.queue <- event:
+	case <-timer.C:
+		log.Println("timers: queue stayed full, dropping event", event.Type, "of game", event.GameCode)
+	}
+}
+
+func (scheduler *Scheduler) run() {
+	for event := range scheduler.queue {
+		scheduler.observe(event)
+	}
+}
+
+func (scheduler *Scheduler) observe(event events.Event) {
+	switch event.Type {
+	case events.GameStarted, events.TurnChanged:
+		scheduler.schedule(event.GameCode)
+	case events.GameOver, events.GameCancelled:
+		scheduler.stop(event.GameCode)
+	case events.PlayerDisconnected:
+		scheduler.leave(absence{code: event.GameCode, userId: event.UserID})
+	case events.PlayerConnected, events.PlayerForfeited:
+		scheduler.back(absence{code: event.GameCode, userId: event.UserID})
+	}
+}
+
+// schedule arms the game's timer for the current turn's deadline, replacing
+// the previous one.
+func (scheduler *Scheduler) schedule(code string) {
+	deadline, err := scheduler.deadline(code)
+
+	if err != nil {
+		log.Println("timers: failed to read the turn deadline:", err)
+		return
+	}
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	if timer := scheduler.turns[code]; timer != nil {
+		timer.Stop()
+		delete(scheduler.turns, code)
+	}
+
+	if deadline.IsZero() {
+		return
+	}
+
+	scheduler.turns[code] = time.AfterFunc(time.Until(deadline), func() {
+		if err := scheduler.turnService.Expire(code); err != nil {
+			log.Println("timers: failed to expire the turn:", err)
+		}
+
+		// the turn has usually moved on and rescheduled the game already,
+		// but a deadline that moved without an event needs a new timer
+		scheduler.schedule(code)
+	})
+}
+
+// deadline is when the current turn of the game runs out, zero when it is
+// not timed.
+func (scheduler *Scheduler) deadline(code string) (time.Time, error) {
+	game, err := scheduler.gameRepo.FindByCode(code)
+
+	if err != nil || game.State == nil || !game.IsTimed() || game.IsCancelled() {
+		return time.Time{}, err
+	}
+
+	players, err := scheduler.gameRepo.FindPlayers(game.ID)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, p := range players {
+		if p.UserID == game.State.CurrentUserID {
+			return game.TurnDeadline(p), nil
+		}
+	}
+
+	return time.Time{}, nil
+}
+
+// stop drops every timer of a game that is over.
+func (scheduler *Scheduler) stop(code string) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	if timer := scheduler.turns[code]; timer != nil {
+		timer.Stop()
+		delete(scheduler.turns, code)
+	}
+
+	for key, timer := range scheduler.absent {
+		if key.code == code {
+			timer.Stop()
+			delete(scheduler.absent, key)
+		}
+	}
+}
+
+// leave gives a player who disconnected from a timed game in play the
+// grace period to come back. Leaving a lobby arms nothing.
+func (scheduler *Scheduler) leave(key absence) {
+	if scheduler.grace <= 0 {
+		return
+	}
+
+	game, err := scheduler.gameRepo.FindByCode(key.code)
+
+	if err != nil {
+		log.Println("timers: failed to read the game:", err)
+		return
+	}
+
+	if !game.IsTimed() || !game.IsStarted() || game.IsFinished() || game.IsCancelled() {
+		return
+	}
+
+	leftAt := time.Now()
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	if scheduler.absent[key] != nil {
+		return
+	}
+
+	scheduler.absent[key] = time.AfterFunc(scheduler.grace, func() {
+		scheduler.mu.Lock()
+		delete(scheduler.absent, key)
+		scheduler.mu.Unlock()
+
+		if err := scheduler.turnService.Abandon(key.code, key.userId, leftAt); err != nil {
+			log.Println("timers: failed to forfeit for an absent player:", err)
+		}
+	})
+}
+
+// back cancels the grace period of a player who reconnected or is out of
+// the game anyway.
+func (scheduler *Scheduler) back(key absence) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	if timer := scheduler.absent[key]; timer != nil {
+		timer.Stop()
+		delete(scheduler.absent, key)
+	}
+}